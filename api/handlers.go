@@ -0,0 +1,255 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/export"
+)
+
+// Сколько ждать запуска интервала, прежде чем ответить на start
+const startTimeout = 2 * time.Second
+
+// Интервал в ответах API - запись выгрузки и оставшееся время
+type Interval struct {
+	export.Record
+	// Сколько осталось до истечения; 0 - истекло или открытый интервал
+	RemainingSeconds int64 `json:"remaining_seconds"`
+}
+
+// Формирует интервал для ответа
+func NewInterval(i pomodoro.Interval) Interval {
+	remaining := i.PlannedDuration - i.ActualDuration
+	if i.OpenEnded() || remaining < 0 {
+		remaining = 0
+	}
+	return Interval{
+		Record:           export.NewRecord(i),
+		RemainingSeconds: int64(remaining / time.Second),
+	}
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/v1/interval", s.current)
+	s.mux.HandleFunc("POST /api/v1/interval/start", s.start)
+	s.mux.HandleFunc("POST /api/v1/interval/pause", s.action((pomodoro.Interval).Pause))
+	s.mux.HandleFunc("POST /api/v1/interval/finish", s.action((pomodoro.Interval).Finish))
+	s.mux.HandleFunc("POST /api/v1/interval/cancel", s.action((pomodoro.Interval).Cancel))
+	s.mux.HandleFunc("POST /api/v1/interval/reset", s.action((pomodoro.Interval).Reset))
+	s.mux.HandleFunc("POST /api/v1/interval/skip", s.skip)
+	s.mux.HandleFunc("POST /api/v1/interval/interrupt", s.interrupt)
+	s.mux.HandleFunc("GET /api/v1/history", s.history)
+	s.mux.HandleFunc("GET /api/v1/events", s.events)
+	// Метрики для Prometheus - вне /api/v1, по привычному адресу
+	s.mux.Handle("GET /metrics", s.metrics)
+}
+
+// GET /api/v1/interval - текущий интервал. Если его нет - следующий
+// по расписанию: чтение ничего не создаёт, ID у такого интервала нулевой.
+func (s *Server) current(w http.ResponseWriter, r *http.Request) {
+	i, err := pomodoro.Current(s.config)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, NewInterval(i))
+}
+
+// POST /api/v1/interval/start - запустить или продолжить текущий интервал.
+// Start блокируется до конца интервала, поэтому исполняем его в фоне,
+// а ответ отправляем, как только интервал запустился.
+func (s *Server) start(w http.ResponseWriter, r *http.Request) {
+	i, err := pomodoro.GetInterval(s.config)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if i.State == pomodoro.StateRunning {
+		writeJSON(w, http.StatusOK, NewInterval(i))
+		return
+	}
+
+	started := make(chan pomodoro.Interval, 1)
+	unsubscribe := s.config.Subscribe(func(e pomodoro.Event) {
+		if e.Interval.ID != i.ID ||
+			(e.Type != pomodoro.EventStart && e.Type != pomodoro.EventResume) {
+			return
+		}
+		select {
+		case started <- e.Interval:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	// Start возвращается сразу, если интервал с ошибкой не запустился
+	// или его уже запустил кто-то другой - например, TUI в тот же миг
+	returned := make(chan error, 1)
+	go func() {
+		noop := func(pomodoro.Interval) {}
+		err := i.Start(s.ctx, s.config, noop, noop, noop)
+		returned <- err
+		if err != nil {
+			s.opts.OnError(err)
+		}
+	}()
+
+	select {
+	case i = <-started:
+		writeJSON(w, http.StatusOK, NewInterval(i))
+	case err := <-returned:
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		if i, err = s.config.Repository().ByID(i.ID); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, NewInterval(i))
+	case <-time.After(startTimeout):
+		writeError(w, http.StatusGatewayTimeout, errors.New("интервал не запустился"))
+	}
+}
+
+// Действие над текущим интервалом - пауза, завершение, отмена, сброс
+func (s *Server) action(f func(pomodoro.Interval, *pomodoro.IntevalConfig) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Действовать можно только над уже созданным интервалом -
+		// GetInterval создал бы новый только ради ошибки
+		i, err := s.config.Repository().Last()
+		if errors.Is(err, pomodoro.ErrNoIntervals) {
+			writeError(w, http.StatusConflict, pomodoro.ErrIntervalNotRunning)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if err := f(i, s.config); err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		if i, err = s.config.Repository().ByID(i.ID); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, NewInterval(i))
+	}
+}
+
+// POST /api/v1/interval/skip - пропустить текущий интервал, в ответе - следующий
+func (s *Server) skip(w http.ResponseWriter, r *http.Request) {
+	i, err := pomodoro.Skip(s.config)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, NewInterval(i))
+}
+
+// Тело запроса interrupt
+type InterruptRequest struct {
+	Kind string `json:"kind"`
+	Note string `json:"note"`
+}
+
+// Ответ на interrupt - интервал и число его прерываний по видам
+type InterruptResponse struct {
+	Interval
+	Internal int `json:"internal_interruptions"`
+	External int `json:"external_interruptions"`
+}
+
+// POST /api/v1/interval/interrupt - записать прерывание текущего интервала,
+// не останавливая его. Тело - {"kind": "internal" или "external", "note": "..."};
+// без тела - внутреннее прерывание без заметки.
+func (s *Server) interrupt(w http.ResponseWriter, r *http.Request) {
+	req := InterruptRequest{Kind: pomodoro.InterruptionInternal}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("неверное тело запроса: %w", err))
+		return
+	}
+
+	i, err := pomodoro.Interrupt(s.config, req.Kind, req.Note)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, InterruptResponse{
+		Interval: NewInterval(i),
+		Internal: i.InterruptionCount(pomodoro.InterruptionInternal),
+		External: i.InterruptionCount(pomodoro.InterruptionExternal),
+	})
+}
+
+// GET /api/v1/history?from=&to=&category=&tag=&limit= - история интервалов.
+// Даты - 2006-01-02 или RFC 3339, category и tag можно повторять.
+// limit оставляет самые свежие интервалы.
+func (s *Server) history(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := pomodoro.Filter{Categories: q["category"], Tags: q["tag"]}
+
+	var err error
+	if f.From, err = pomodoro.ParseDate(q.Get("from"), false); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if f.To, err = pomodoro.ParseDate(q.Get("to"), true); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit := 0
+	if l := q.Get("limit"); l != "" {
+		if limit, err = strconv.Atoi(l); err != nil || limit < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("неверный limit %q", l))
+			return
+		}
+	}
+
+	intervals, err := pomodoro.Query(s.config.Repository(), f)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if limit > 0 && len(intervals) > limit {
+		intervals = intervals[len(intervals)-limit:]
+	}
+
+	res := make([]Interval, 0, len(intervals))
+	for _, i := range intervals {
+		res = append(res, NewInterval(i))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// HTTP-статус для ошибки интервала: недопустимое действие - конфликт,
+// неверные данные запроса - 400
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, pomodoro.ErrInvalidInterruption):
+		return http.StatusBadRequest
+	case errors.Is(err, pomodoro.ErrIntervalNotRunning),
+		errors.Is(err, pomodoro.ErrIntervalCompleted),
+		errors.Is(err, pomodoro.ErrInvalidState):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
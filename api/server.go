@@ -0,0 +1,137 @@
+// Встроенный HTTP API для управления таймером из редакторов и скриптов.
+//
+// Сервер слушает только localhost и работает поверх тех же GetInterval,
+// Start и Pause, что и TUI, - с тем же конфигом и репозиторием, поэтому
+// интервал, запущенный через API, виден в TUI и наоборот.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/metrics"
+)
+
+// Порт по умолчанию
+const DefaultPort = 7465
+
+// Настройки сервера
+type Options struct {
+	// Порт на localhost; 0 - любой свободный
+	Port int
+	// Если задан - запросы должны нести заголовок Authorization: Bearer <Token>
+	Token string
+	// Обработчик ошибок интервалов, запущенных через API: исполняются они
+	// в фоне, и вернуть ошибку в ответе уже некому. По умолчанию - в лог.
+	OnError func(error)
+}
+
+// HTTP-сервер API
+type Server struct {
+	config *pomodoro.IntevalConfig
+	opts   Options
+	// Контекст исполнения интервалов, запущенных через API
+	ctx     context.Context
+	mux     *http.ServeMux
+	stream  *stream
+	metrics *metrics.Collector
+}
+
+// Создаёт сервер. Интервалы, запущенные через API, исполняются
+// в контексте ctx - как и запущенные из TUI.
+func New(ctx context.Context, config *pomodoro.IntevalConfig, opts Options) *Server {
+	if opts.OnError == nil {
+		opts.OnError = func(err error) { slog.Error("API: интервал", "error", err) }
+	}
+	s := &Server{
+		config:  config,
+		opts:    opts,
+		ctx:     ctx,
+		mux:     http.NewServeMux(),
+		stream:  newStream(),
+		metrics: metrics.NewCollector(),
+	}
+	s.routes()
+
+	unsubscribeStream := config.Subscribe(s.stream.publish)
+	unsubscribeMetrics := config.Subscribe(s.metrics.Observe)
+	go func() {
+		<-ctx.Done()
+		unsubscribeStream()
+		unsubscribeMetrics()
+	}()
+	return s
+}
+
+// Обработчик всех запросов API с проверкой хоста и токена
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Защита от DNS rebinding: сайт в браузере может указать свой домен
+		// на 127.0.0.1, но заголовок Host останется его доменом
+		if !localHost(r.Host) {
+			writeError(w, http.StatusForbidden, errors.New("доступ только через localhost"))
+			return
+		}
+		if s.opts.Token != "" && !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="pomo"`)
+			writeError(w, http.StatusUnauthorized, errors.New("нужен токен доступа"))
+			return
+		}
+		s.mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+// Хост запроса - localhost или loopback-адрес
+func localHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// Слушает 127.0.0.1:Port, пока не завершится ctx сервера
+func (s *Server) ListenAndServe() error {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", s.opts.Port))
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Обслуживает запросы на ln, пока не завершится ctx сервера
+func (s *Server) Serve(ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-s.ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
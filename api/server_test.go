@@ -0,0 +1,301 @@
+package api_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/api"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/repository"
+)
+
+func newServer(t *testing.T, token string) (*pomodoro.IntevalConfig, http.Handler) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	config := pomodoro.NewConfig(repository.NewInMemoryRepo(), time.Minute, time.Minute, time.Minute)
+	s := api.New(ctx, config, api.Options{
+		Token:   token,
+		OnError: func(err error) { t.Errorf("Не ожидали ошибку интервала: %v", err) },
+	})
+	return config, s.Handler()
+}
+
+// Выполняет запрос и разбирает JSON-ответ в v
+func do(t *testing.T, h http.Handler, method, target string, v any, opts ...func(*http.Request)) int {
+	t.Helper()
+	r := httptest.NewRequest(method, target, nil)
+	r.Host = "localhost:7465"
+	for _, o := range opts {
+		o(r)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if v != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("Неверный JSON %q: %v", w.Body.String(), err)
+		}
+	}
+	return w.Code
+}
+
+func TestControl(t *testing.T) {
+	_, h := newServer(t, "")
+
+	testCases := []struct {
+		method    string
+		target    string
+		expStatus int
+		expState  string
+	}{
+		{http.MethodGet, "/api/v1/interval", http.StatusOK, "not_started"},
+		{http.MethodPost, "/api/v1/interval/pause", http.StatusConflict, ""},
+		{http.MethodPost, "/api/v1/interval/start", http.StatusOK, "running"},
+		{http.MethodPost, "/api/v1/interval/start", http.StatusOK, "running"},
+		{http.MethodPost, "/api/v1/interval/pause", http.StatusOK, "paused"},
+		{http.MethodPost, "/api/v1/interval/start", http.StatusOK, "running"},
+		{http.MethodPost, "/api/v1/interval/reset", http.StatusOK, "running"},
+		{http.MethodPost, "/api/v1/interval/cancel", http.StatusOK, "cancelled"},
+		{http.MethodPost, "/api/v1/interval/reset", http.StatusConflict, ""},
+		{http.MethodPost, "/api/v1/interval/skip", http.StatusOK, "not_started"},
+		{http.MethodGet, "/api/v1/interval/start", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+tc.target, func(t *testing.T) {
+			var res struct {
+				api.Interval
+				Error string `json:"error"`
+			}
+			var v any = &res
+			if tc.expStatus == http.StatusMethodNotAllowed {
+				v = nil
+			}
+			status := do(t, h, tc.method, tc.target, v)
+			if status != tc.expStatus {
+				t.Fatalf("Ожидали статус: %d, а получили: %d (%s)", tc.expStatus, status, res.Error)
+			}
+			if tc.expState != "" && res.State != tc.expState {
+				t.Errorf("Ожидали состояние: %q, а получили: %q", tc.expState, res.State)
+			}
+		})
+	}
+
+	// Отменили первый Pomodoro, пропустили короткий перерыв - в истории оба
+	var history []api.Interval
+	if status := do(t, h, http.MethodGet, "/api/v1/history", &history); status != http.StatusOK {
+		t.Fatalf("Ожидали статус: %d, а получили: %d", http.StatusOK, status)
+	}
+	if len(history) != 2 || history[0].Category != pomodoro.CategoryPomodoro ||
+		history[1].Category != pomodoro.CategoryShortBreak || history[1].State != "cancelled" {
+		t.Errorf("Ожидали в истории отменённые Pomodoro и перерыв, а получили: %+v", history)
+	}
+}
+
+func TestAccess(t *testing.T) {
+	_, h := newServer(t, "secret")
+
+	bearer := func(token string) func(*http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+	host := func(host string) func(*http.Request) {
+		return func(r *http.Request) { r.Host = host }
+	}
+
+	testCases := []struct {
+		name      string
+		opts      []func(*http.Request)
+		expStatus int
+	}{
+		{"NoToken", nil, http.StatusUnauthorized},
+		{"WrongToken", []func(*http.Request){bearer("guess")}, http.StatusUnauthorized},
+		{"Token", []func(*http.Request){bearer("secret")}, http.StatusOK},
+		{"Loopback", []func(*http.Request){bearer("secret"), host("127.0.0.1:7465")}, http.StatusOK},
+		{"ForeignHost", []func(*http.Request){bearer("secret"), host("evil.example:7465")}, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := do(t, h, http.MethodGet, "/api/v1/interval", nil, tc.opts...)
+			if status != tc.expStatus {
+				t.Errorf("Ожидали статус: %d, а получили: %d", tc.expStatus, status)
+			}
+		})
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	_, h := newServer(t, "")
+
+	body := func(b string) func(*http.Request) {
+		return func(r *http.Request) { r.Body = io.NopCloser(strings.NewReader(b)) }
+	}
+
+	// Прерывать пока нечего
+	if status := do(t, h, http.MethodPost, "/api/v1/interval/interrupt", nil); status != http.StatusConflict {
+		t.Fatalf("Ожидали статус: %d, а получили: %d", http.StatusConflict, status)
+	}
+	if status := do(t, h, http.MethodPost, "/api/v1/interval/start", nil); status != http.StatusOK {
+		t.Fatalf("Ожидали статус: %d, а получили: %d", http.StatusOK, status)
+	}
+
+	testCases := []struct {
+		name        string
+		body        string
+		expStatus   int
+		expInternal int
+		expExternal int
+	}{
+		{name: "NoBody", expStatus: http.StatusOK, expInternal: 1},
+		{name: "External", body: `{"kind": "external", "note": "звонок"}`,
+			expStatus: http.StatusOK, expInternal: 1, expExternal: 1},
+		{name: "UnknownKind", body: `{"kind": "coffee"}`, expStatus: http.StatusBadRequest},
+		{name: "BadJSON", body: `{"kind":`, expStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var res struct {
+				api.InterruptResponse
+				Error string `json:"error"`
+			}
+			status := do(t, h, http.MethodPost, "/api/v1/interval/interrupt", &res, body(tc.body))
+			if status != tc.expStatus {
+				t.Fatalf("Ожидали статус: %d, а получили: %d (%s)", tc.expStatus, status, res.Error)
+			}
+			if status != http.StatusOK {
+				return
+			}
+			if res.State != "running" || res.Internal != tc.expInternal || res.External != tc.expExternal {
+				t.Errorf("Ожидали исполняющийся интервал с прерываниями %d/%d, а получили: %+v",
+					tc.expInternal, tc.expExternal, res.InterruptResponse)
+			}
+		})
+	}
+}
+
+func TestHistoryBadRequest(t *testing.T) {
+	_, h := newServer(t, "")
+
+	for _, q := range []string{"from=вчера", "limit=-1"} {
+		var res map[string]string
+		status := do(t, h, http.MethodGet, "/api/v1/history?"+q, &res)
+		if status != http.StatusBadRequest || !strings.Contains(res["error"], "неверн") {
+			t.Errorf("%s: ожидали статус %d с ошибкой, а получили: %d %v",
+				q, http.StatusBadRequest, status, res)
+		}
+	}
+}
+
+// Читает из потока SSE следующее событие: id и тип
+func readEvent(t *testing.T, r *bufio.Reader) (id, typ string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && typ != "":
+			return id, typ
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			typ = strings.TrimPrefix(line, "event: ")
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	_, h := newServer(t, "")
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	connect := func(lastID string) (*http.Response, *bufio.Reader) {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/events", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lastID != "" {
+			req.Header.Set("Last-Event-ID", lastID)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("Ожидали text/event-stream, а получили: %q", ct)
+		}
+		return res, bufio.NewReader(res.Body)
+	}
+
+	res, r := connect("")
+	if _, typ := readEvent(t, r); typ != "snapshot" {
+		t.Fatalf("Ожидали первым снимок, а получили: %q", typ)
+	}
+
+	if status := do(t, h, http.MethodPost, "/api/v1/interval/start", nil); status != http.StatusOK {
+		t.Fatalf("Ожидали статус: %d, а получили: %d", http.StatusOK, status)
+	}
+	startID, typ := readEvent(t, r)
+	if typ != pomodoro.EventStart {
+		t.Fatalf("Ожидали событие %q, а получили: %q", pomodoro.EventStart, typ)
+	}
+	if _, typ := readEvent(t, r); typ != pomodoro.EventTick {
+		t.Fatalf("Ожидали событие %q, а получили: %q", pomodoro.EventTick, typ)
+	}
+	res.Body.Close()
+
+	// Пока клиента нет - пауза; при переподключении он её получит
+	if status := do(t, h, http.MethodPost, "/api/v1/interval/pause", nil); status != http.StatusOK {
+		t.Fatalf("Ожидали статус: %d, а получили: %d", http.StatusOK, status)
+	}
+
+	res, r = connect(startID)
+	defer res.Body.Close()
+	if _, typ := readEvent(t, r); typ != pomodoro.EventTick {
+		t.Errorf("Ожидали пропущенный %q, а получили: %q", pomodoro.EventTick, typ)
+	}
+	// Дальше ещё тики, если успели, и пауза
+	for {
+		_, typ := readEvent(t, r)
+		if typ == pomodoro.EventTick {
+			continue
+		}
+		if typ != pomodoro.EventPause {
+			t.Errorf("Ожидали пропущенную %q, а получили: %q", pomodoro.EventPause, typ)
+		}
+		break
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	_, h := newServer(t, "")
+
+	if status := do(t, h, http.MethodPost, "/api/v1/interval/start", nil); status != http.StatusOK {
+		t.Fatalf("Ожидали статус: %d, а получили: %d", http.StatusOK, status)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	r.Host = "localhost:7465"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("Ожидали метрики, а получили: %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	exp := `pomo_intervals_started_total{category="Pomodoro"} 1`
+	if !strings.Contains(w.Body.String(), exp) {
+		t.Errorf("Ожидали строку %q в:\n%s", exp, w.Body.String())
+	}
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+const (
+	// Сколько последних событий помним для переподключения по Last-Event-ID
+	streamHistory = 256
+	// Буфер событий клиента. Переполнился - клиент не успевает читать,
+	// отключаем его: tick ждать клиента не должен.
+	streamBuffer = 64
+	// Интервал комментариев-пингов, чтобы прокси не закрывали соединение
+	streamPing = 15 * time.Second
+	// Через сколько миллисекунд клиенту переподключаться
+	streamRetry = 3000
+)
+
+// Событие в потоке SSE. ID - "<эпоха>-<номер>": эпоха - время запуска
+// сервера в наносекундах, так что номер из прошлого запуска не спутать
+// с номером из нынешнего.
+type StreamEvent struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	Interval Interval  `json:"interval"`
+
+	seq uint64
+}
+
+// Рассылка событий интервалов клиентам SSE
+type stream struct {
+	mu      sync.Mutex
+	epoch   int64
+	lastSeq uint64
+	history []StreamEvent
+	clients map[chan StreamEvent]struct{}
+}
+
+func newStream() *stream {
+	return &stream{epoch: time.Now().UnixNano(), clients: map[chan StreamEvent]struct{}{}}
+}
+
+// Собирает id события из эпохи и номера
+func eventID(epoch int64, seq uint64) string {
+	return fmt.Sprintf("%d-%d", epoch, seq)
+}
+
+// Разбирает id события. ok == false - id не наш, продолжить по нему нельзя.
+func parseEventID(id string) (epoch int64, seq uint64, ok bool) {
+	e, n, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+	epoch, err := strconv.ParseInt(e, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if seq, err = strconv.ParseUint(n, 10, 64); err != nil {
+		return 0, 0, false
+	}
+	return epoch, seq, true
+}
+
+// Подписчик на события конфига. Никогда не блокируется:
+// медленный клиент, чей буфер переполнен, отключается.
+func (st *stream) publish(e pomodoro.Event) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.lastSeq++
+	se := StreamEvent{
+		ID:       eventID(st.epoch, st.lastSeq),
+		Type:     e.Type,
+		Time:     e.Time,
+		Interval: NewInterval(e.Interval),
+		seq:      st.lastSeq,
+	}
+
+	st.history = append(st.history, se)
+	if len(st.history) > streamHistory {
+		st.history = st.history[len(st.history)-streamHistory:]
+	}
+
+	for c := range st.clients {
+		select {
+		case c <- se:
+		default:
+			// Клиент переподключится и дочитает пропущенное по Last-Event-ID
+			delete(st.clients, c)
+			close(c)
+		}
+	}
+}
+
+// Подключает клиента. Возвращает канал событий и события после lastID,
+// которые клиент пропустил. replayed == false - продолжить с lastID нельзя
+// (id пустой, из другого запуска или события уже забыты), клиенту нужен снимок.
+func (st *stream) subscribe(lastID string) (c chan StreamEvent, missed []StreamEvent, replayed bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	c = make(chan StreamEvent, streamBuffer)
+	st.clients[c] = struct{}{}
+
+	epoch, seq, ok := parseEventID(lastID)
+	if !ok || epoch != st.epoch || seq == 0 || seq > st.lastSeq {
+		return c, nil, false
+	}
+	if seq == st.lastSeq {
+		return c, nil, true
+	}
+	if len(st.history) == 0 || st.history[0].seq > seq+1 {
+		return c, nil, false
+	}
+	for _, e := range st.history {
+		if e.seq > seq {
+			missed = append(missed, e)
+		}
+	}
+	return c, missed, true
+}
+
+func (st *stream) unsubscribe(c chan StreamEvent) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if _, ok := st.clients[c]; ok {
+		delete(st.clients, c)
+		close(c)
+	}
+}
+
+// GET /api/v1/events - поток событий в формате Server-Sent Events.
+// Событие - на каждый тик, каждую смену состояния и задачи. При подключении без
+// Last-Event-ID (или если пропущенные события уже забыты) первым приходит
+// снимок текущего интервала - событие snapshot без id.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+
+	c, missed, replayed := s.stream.subscribe(r.Header.Get("Last-Event-ID"))
+	defer s.stream.unsubscribe(c)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+
+	if !replayed {
+		i, err := pomodoro.Current(s.config)
+		if err != nil {
+			s.opts.OnError(err)
+			return
+		}
+		if err := writeEvent(w, StreamEvent{Type: "snapshot", Time: time.Now(), Interval: NewInterval(i)}); err != nil {
+			return
+		}
+	}
+	for _, e := range missed {
+		if err := writeEvent(w, e); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ping := time.NewTicker(streamPing)
+	defer ping.Stop()
+
+	for {
+		select {
+		case e, ok := <-c:
+			if !ok {
+				// Отключили как медленного - клиент переподключится сам
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		case <-ping.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-s.ctx.Done():
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e StreamEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if e.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", e.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+	return err
+}
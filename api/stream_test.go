@@ -0,0 +1,79 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestStreamSlowClient(t *testing.T) {
+	st := newStream()
+	slow, _, _ := st.subscribe("")
+	fast, _, _ := st.subscribe("")
+
+	// Быстрый клиент читает, медленный - нет. publish не должен блокироваться.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range streamBuffer * 2 {
+			st.publish(pomodoro.Event{Type: pomodoro.EventTick, Time: time.Now()})
+			<-fast
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish заблокировался на медленном клиенте")
+	}
+
+	// Медленного отключили: в канале остались события до переполнения, потом он закрыт
+	n := 0
+	for range slow {
+		n++
+	}
+	if n != streamBuffer {
+		t.Errorf("Ожидали у медленного клиента событий: %d, а получили: %d", streamBuffer, n)
+	}
+}
+
+func TestStreamResume(t *testing.T) {
+	st := newStream()
+	for range streamHistory + 10 {
+		st.publish(pomodoro.Event{Type: pomodoro.EventTick})
+	}
+	last := st.lastSeq
+
+	testCases := []struct {
+		name        string
+		lastID      string
+		expReplayed bool
+		expMissed   int
+	}{
+		{name: "NoHeader", expReplayed: false},
+		{name: "UpToDate", lastID: eventID(st.epoch, last), expReplayed: true},
+		{name: "Missed", lastID: eventID(st.epoch, last-5), expReplayed: true, expMissed: 5},
+		{name: "Forgotten", lastID: eventID(st.epoch, 3), expReplayed: false},
+		// Номер из прошлого запуска может совпасть с нынешним - решает эпоха
+		{name: "OtherRun", lastID: eventID(st.epoch-1, last-5), expReplayed: false},
+		{name: "Future", lastID: eventID(st.epoch, last+100), expReplayed: false},
+		{name: "OldFormat", lastID: "42", expReplayed: false},
+		{name: "Garbage", lastID: "x-1", expReplayed: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, missed, replayed := st.subscribe(tc.lastID)
+			defer st.unsubscribe(c)
+
+			if replayed != tc.expReplayed || len(missed) != tc.expMissed {
+				t.Errorf("Ожидали replayed=%v и пропущенных %d, а получили: %v и %d",
+					tc.expReplayed, tc.expMissed, replayed, len(missed))
+			}
+			if exp := eventID(st.epoch, last-4); len(missed) > 0 && missed[0].ID != exp {
+				t.Errorf("Ожидали первым событие %s, а получили: %s", exp, missed[0].ID)
+			}
+		})
+	}
+}
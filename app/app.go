@@ -3,67 +3,267 @@ package app
 import (
 	"context"
 	"image"
+	"sync/atomic"
 	"time"
 
 	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/terminal/tcell"
 	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/text"
 	"vegorov.ru/go-cli/pomo/pomodoro"
 )
 
+// Настройки TUI
+type Options struct {
+	Theme Theme
+	Idle  IdleOptions
+	// Не больше компактной раскладки, даже если терминал большой
+	Compact bool
+	// Раскладка клавиш; nil - по умолчанию
+	Keys Keymap
+	Quit QuitOptions
+}
+
 type App struct {
 	ctx        context.Context
+	cancel     context.CancelFunc
 	controller *termdash.Controller
 	redrawCh   chan bool
 	errorCh    chan error
 	term       *tcell.Terminal
 	size       image.Point
+	w          *widgets
+	config     *pomodoro.IntevalConfig
+	opts       Options
+	idle       *idle
+	b          *buttonsSet
+	act        actions
+	bindings   bindings
+	help       *text.Text
+	picker     *taskPicker
+	layout     int
+	// Окно поверх раскладки: пишет Run, читает обработчик клавиш
+	overlay   atomic.Int32
+	overlayCh chan int
+	// Выход с интервалом, оставленным в фоне, - без отмены контекста
+	detachCh chan struct{}
+	// Опции контроллера - он пересоздаётся при смене раскладки
+	ctrlOpts []termdash.Option
 }
 
-func New(config *pomodoro.IntevalConfig) (*App, error) {
-	ctx, cancel := context.WithCancel(context.Background())
-
-	quitter := func(k *terminalapi.Keyboard) {
-		if k.Key == 'q' || k.Key == 'Q' {
-			cancel()
-		}
+func New(config *pomodoro.IntevalConfig, opts Options) (*App, error) {
+	if opts.Keys == nil {
+		opts.Keys = DefaultKeymap()
+	}
+	// Без фонового режима выбирать его по умолчанию нельзя - тогда пауза
+	if opts.Quit.Default == "" || opts.Quit.Default == QuitBackground && opts.Quit.Background == nil {
+		opts.Quit.Default = QuitPause
 	}
+	bindings, err := opts.Keys.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 
 	redrawCh := make(chan bool)
 	errorCh := make(chan error)
 
-	w, err := newWidgets(ctx, errorCh)
+	w, err := newWidgets(ctx, opts.Theme, errorCh)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	b, err := newButtonSet(ctx, config, w, redrawCh, errorCh)
+	config.Subscribe(display(config, w, redrawCh))
+	idle := newIdle()
+	config.Subscribe(idle.observe)
+
+	act := newActions(ctx, config, errorCh)
+	b, err := newButtonSet(act, opts.Keys, opts.Theme)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	term, err := tcell.New()
+	help, err := newHelp(opts.Keys, opts.Theme)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	c, err := newGrid(b, w, term)
+	term, err := tcell.New(tcell.ClearStyle(opts.Theme.Text, opts.Theme.Background))
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	controller, err := termdash.NewController(term, c, termdash.KeyboardSubscriber(quitter))
-	if err != nil {
+	a := &App{
+		ctx:       ctx,
+		cancel:    cancel,
+		redrawCh:  redrawCh,
+		errorCh:   errorCh,
+		term:      term,
+		w:         w,
+		config:    config,
+		opts:      opts,
+		idle:      idle,
+		b:         b,
+		act:       act,
+		bindings:  bindings,
+		help:      help,
+		picker:    &taskPicker{},
+		overlayCh: make(chan int, 1),
+		detachCh:  make(chan struct{}, 1),
+	}
+	// Любая клавиша или движение мыши - активность для детектора простоя
+	a.ctrlOpts = []termdash.Option{
+		termdash.KeyboardSubscriber(a.keyboard),
+		termdash.MouseSubscriber(func(*terminalapi.Mouse) { idle.touch() }),
+	}
+
+	a.size = term.Size()
+	if err := a.relayout(chooseLayout(a.size, opts.Compact)); err != nil {
+		cancel()
+		term.Close()
 		return nil, err
 	}
+	return a, nil
+}
+
+// Обработчик клавиш. Пока открыто окно, клавиши достаются ему:
+// справку закрывает любая клавиша, кроме выхода, ввод задачи и окно
+// выхода - Esc. Выход без вопросов работает всегда.
+func (a *App) keyboard(k *terminalapi.Keyboard) {
+	a.idle.touch()
+
+	action, ok := a.bindings.action(k.Key)
+	if ok && action == ActionForceQuit {
+		a.cancel()
+		return
+	}
+	switch a.overlay.Load() {
+	case overlayHelp:
+		if !ok || action != ActionQuit {
+			a.showOverlay(overlayNone)
+			return
+		}
+	case overlayTask:
+		if k.Key == keyboard.KeyEsc {
+			a.showOverlay(overlayNone)
+		}
+		return
+	case overlayTasks:
+		switch k.Key {
+		case keyboard.KeyEsc:
+			a.showOverlay(overlayNone)
+		case keyboard.KeyArrowUp, keyboard.KeyArrowDown:
+			delta := 1
+			if k.Key == keyboard.KeyArrowUp {
+				delta = -1
+			}
+			if err := a.picker.move(delta, a.opts.Theme); err != nil {
+				go func() { a.errorCh <- err }()
+				return
+			}
+			go func() { a.redrawCh <- true }()
+		case keyboard.KeyEnter:
+			if t, ok := a.picker.selected(); ok {
+				go attachTask(a.config, t, a.w, a.redrawCh, a.errorCh)
+			}
+			a.showOverlay(overlayNone)
+		}
+		return
+	case overlayQuit:
+		switch k.Key {
+		case keyboard.KeyEsc:
+			a.showOverlay(overlayNone)
+		case keyboard.KeyEnter:
+			go a.quitWith(a.opts.Quit.Default)
+		default:
+			if choice, ok := a.quitChoice(rune(k.Key)); ok {
+				go a.quitWith(choice)
+			}
+		}
+		return
+	}
+	if ok {
+		a.do(action)
+	}
+}
+
+// Исполняет действие из раскладки клавиш
+func (a *App) do(action string) {
+	switch action {
+	case ActionStart:
+		a.act.start()
+	case ActionPause:
+		a.act.pause()
+	case ActionFinish:
+		a.act.finish()
+	case ActionSkip:
+		go skip(a.config, a.w, a.redrawCh, a.errorCh)
+	case ActionCancel:
+		go cancelInterval(a.config, a.w, a.redrawCh, a.errorCh)
+	case ActionReset:
+		go reset(a.config, a.w, a.redrawCh, a.errorCh)
+	case ActionTask:
+		a.showOverlay(overlayTask)
+	case ActionPickTask:
+		go a.openPicker()
+	case ActionInterruptInternal:
+		go interrupt(a.config, pomodoro.InterruptionInternal, a.w, a.redrawCh, a.errorCh)
+	case ActionInterruptExternal:
+		go interrupt(a.config, pomodoro.InterruptionExternal, a.w, a.redrawCh, a.errorCh)
+	case ActionProfile:
+		go nextProfile(a.config, a.w, a.redrawCh, a.errorCh)
+	case ActionHelp:
+		a.showOverlay(overlayHelp)
+	case ActionQuit:
+		go a.quit()
+	}
+}
+
+// Переключает раскладку: собирает контейнер заново и пересоздаёт контроллер.
+// Обновлять существующий контейнер через Update ненадёжно - настройки
+// разбиения прежней раскладки остаются в нём и ломают новую.
+// Открытое окно (справка, ввод задачи) занимает весь экран вместо раскладки.
+func (a *App) relayout(layout int) error {
+	var c *container.Container
+	var err error
+	if overlay := int(a.overlay.Load()); overlay != overlayNone {
+		var opts []container.Option
+		if opts, err = a.overlayLayout(overlay); err == nil {
+			c, err = container.New(a.term, opts...)
+		}
+	} else {
+		c, err = newGrid(layout, a.b, a.w, a.opts.Keys, a.opts.Theme, a.term)
+	}
+	if err != nil {
+		return err
+	}
+	if a.controller != nil {
+		a.controller.Close()
+	}
+	if a.controller, err = termdash.NewController(a.term, c, a.ctrlOpts...); err != nil {
+		return err
+	}
+	a.layout = layout
+	return nil
+}
+
+// Показывает сообщение в информационной панели.
+// Для событий извне TUI - например, перечитанного конфига.
+func (a *App) Notify(message string) {
+	a.w.update([]int{}, "", " "+message+" ", "", a.redrawCh)
+}
 
-	return &App{
-		ctx:        ctx,
-		controller: controller,
-		redrawCh:   redrawCh,
-		errorCh:    errorCh,
-		term:       term,
-	}, nil
+// Контекст приложения - завершается при выходе из TUI.
+// В нём же исполняются интервалы, запущенные извне TUI (например, через API).
+func (a *App) Context() context.Context {
+	return a.ctx
 }
 
 func (a *App) resize() error {
@@ -75,16 +275,25 @@ func (a *App) resize() error {
 	if err := a.term.Clear(); err != nil {
 		return err
 	}
+	if layout := chooseLayout(a.size, a.opts.Compact); layout != a.layout {
+		// Новый контроллер рисует экран сам
+		return a.relayout(layout)
+	}
 	return a.controller.Redraw()
 }
 
 func (a *App) Run() error {
 	defer a.term.Close()
-	defer a.controller.Close()
+	// Контроллер меняется вместе с раскладкой - закрываем последний
+	defer func() { a.controller.Close() }()
 
-	ticker := time.NewTicker(2 * time.Second)
+	// Размер опрашиваем часто - раскладка должна успевать за изменением окна
+	ticker := time.NewTicker(500 * time.Millisecond)
 	defer ticker.Stop()
 
+	go watchPause(a, a.config)
+	go watchIdle(a, a.config, a.idle, a.opts.Idle)
+
 	for {
 		select {
 		case <-a.redrawCh:
@@ -97,10 +306,20 @@ func (a *App) Run() error {
 			}
 		case <-a.ctx.Done():
 			return nil
+		case <-a.detachCh:
+			return nil
 		case <-ticker.C:
 			if err := a.resize(); err != nil {
 				return err
 			}
+		case overlay := <-a.overlayCh:
+			if int(a.overlay.Load()) == overlay {
+				continue
+			}
+			a.overlay.Store(int32(overlay))
+			if err := a.relayout(a.layout); err != nil {
+				return err
+			}
 		}
 	}
 }
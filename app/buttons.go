@@ -2,43 +2,39 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
+	"unicode/utf8"
 
-	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/widgets/button"
 	"vegorov.ru/go-cli/pomo/pomodoro"
 )
 
 type buttonsSet struct {
-	btStart *button.Button
-	btPause *button.Button
+	btStart  *button.Button
+	btPause  *button.Button
+	btFinish *button.Button
 }
 
-func newButtonSet(ctx context.Context, config *pomodoro.IntevalConfig, w *widgets,
-	redrawCh chan<- bool, errorCh chan<- error,
-) (*buttonsSet, error) {
+// Действия над текущим интервалом - для кнопок и клавиш.
+// Каждое исполняется в своей горутине: Start блокируется до конца интервала.
+type actions struct {
+	start  func()
+	pause  func()
+	finish func()
+}
+
+func newActions(ctx context.Context, config *pomodoro.IntevalConfig, errorCh chan<- error) actions {
+	// Экран обновляет подписчик display - колбэки интервалу не нужны
+	noop := func(pomodoro.Interval) {}
+
 	startInterval := func() {
 		i, err := pomodoro.GetInterval(config)
 		errorCh <- err
 
-		start := func(i pomodoro.Interval) {
-			message := " Возьми перерывчик "
-			if i.Category == pomodoro.CategoryPomodoro {
-				message = " Надо бы поднажать "
-			}
-			w.update([]int{}, i.Category, message, "", redrawCh)
-		}
-
-		end := func(pomodoro.Interval) {
-			w.update([]int{}, "", " Ничего не работает... ", "", redrawCh)
-		}
-
-		periodic := func(i pomodoro.Interval) {
-			w.update([]int{int(i.ActualDuration), int(i.PlannedDuration)}, "", "",
-				fmt.Sprint(i.PlannedDuration-i.ActualDuration), redrawCh)
-		}
-
-		errorCh <- i.Start(ctx, config, start, periodic, end)
+		errorCh <- i.Start(ctx, config, noop, noop, noop)
 	}
 
 	pauseInterval := func() {
@@ -53,33 +49,112 @@ func newButtonSet(ctx context.Context, config *pomodoro.IntevalConfig, w *widget
 				return
 			}
 			errorCh <- err
+		}
+	}
+
+	finishInterval := func() {
+		i, err := pomodoro.GetInterval(config)
+		if err != nil {
+			errorCh <- err
 			return
 		}
-		w.update([]int{}, "", " На паузе.. жми Start для продолжения ", "", redrawCh)
+
+		if err := i.Finish(config); err != nil {
+			if errors.Is(err, pomodoro.ErrIntervalNotRunning) {
+				return
+			}
+			errorCh <- err
+		}
 	}
 
-	btStart, err := button.New(" (s)start ", func() error {
-		go startInterval()
+	return actions{
+		start:  func() { go startInterval() },
+		pause:  func() { go pauseInterval() },
+		finish: func() { go finishInterval() },
+	}
+}
+
+// Кнопки нажимаются мышью; клавиши обрабатывает App по раскладке -
+// в компактной раскладке кнопок на экране нет, а клавиши работать должны.
+// На кнопках подписаны их клавиши.
+func newButtonSet(act actions, keys Keymap, theme Theme) (*buttonsSet, error) {
+	label := func(name, action string) string {
+		if key := keys.key(action); key != "" {
+			return fmt.Sprintf(" %s (%s) ", name, key)
+		}
+		return " " + name + " "
+	}
+	start, pause, finish := label("start", ActionStart), label("pause", ActionPause),
+		label("finish", ActionFinish)
+	// Кнопки одной ширины - по самой длинной подписи
+	width := slices.MaxFunc([]string{start, pause, finish}, func(a, b string) int {
+		return utf8.RuneCountInString(a) - utf8.RuneCountInString(b)
+	})
+
+	btStart, err := button.New(start, func() error {
+		act.start()
 		return nil
 	},
-		button.GlobalKey('s'),
-		button.WidthFor(" (p)ause "),
+		button.FillColor(theme.Start),
+		button.TextColor(theme.ButtonText),
+		button.ShadowColor(theme.Border),
+		button.WidthFor(width),
 		button.Height(3))
 	if err != nil {
 		return nil, err
 	}
 
-	btPause, err := button.New(" (p)ause ", func() error {
-		go pauseInterval()
+	btPause, err := button.New(pause, func() error {
+		act.pause()
 		return nil
 	},
-		button.FillColor(cell.ColorNumber(220)),
-		button.GlobalKey('p'),
+		button.FillColor(theme.Pause),
+		button.TextColor(theme.ButtonText),
+		button.ShadowColor(theme.Border),
+		button.WidthFor(width),
 		button.Height(3),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return &buttonsSet{btStart, btPause}, nil
+	btFinish, err := button.New(finish, func() error {
+		act.finish()
+		return nil
+	},
+		button.FillColor(theme.Finish),
+		button.TextColor(theme.ButtonText),
+		button.ShadowColor(theme.Border),
+		button.WidthFor(width),
+		button.Height(3),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &buttonsSet{btStart, btPause, btFinish}, nil
+}
+
+// Форматирует продолжительность как mm:ss
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// Строка со сводкой за сегодня для информационной панели
+func dailySummary(config *pomodoro.IntevalConfig) string {
+	r, err := pomodoro.DailyReport(config, time.Now())
+	if err != nil {
+		return " Не удалось получить отчёт за сегодня "
+	}
+	c := r.Work
+	s := fmt.Sprintf(" Сегодня рабочих интервалов: %d, %d мин по плану + %d мин переработки",
+		c.Done, int(c.Planned.Minutes()), int(c.Overtime.Minutes()))
+	if c.Internal+c.External > 0 {
+		s += fmt.Sprintf(", прерываний %d/%d", c.Internal, c.External)
+	}
+	if c.Pauses > 0 {
+		s += fmt.Sprintf(", пауз %d на %d мин", c.Pauses, int(c.Paused.Minutes()))
+	}
+	return s + " "
 }
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Обновляет экран по событиям интервалов - кто бы их ни вызвал:
+// кнопки TUI, HTTP API или истечение времени
+func display(config *pomodoro.IntevalConfig, w *widgets, redrawCh chan<- bool) pomodoro.Subscriber {
+	// Интервал, на котором задача вышла за оценку - проверяем при запуске,
+	// а не на каждом тике: подсчёт факта читает всю историю
+	var overID atomic.Int64
+
+	return func(e pomodoro.Event) {
+		i := e.Interval
+		message := " Возьми перерывчик "
+		if !i.Break {
+			message = " Надо бы поднажать "
+		}
+		// Задача могла смениться на ходу - тогда проверяем заново
+		if e.Type == pomodoro.EventStart || e.Type == pomodoro.EventResume || e.Type == pomodoro.EventTask {
+			overID.Store(0)
+			if over, ok := overEstimate(config, i); ok {
+				overID.Store(i.ID)
+				message = over
+			}
+		}
+		// Бублик и индикатор красятся в цвет категории интервала,
+		// а если задача вышла за оценку - в цвет переработки
+		w.setCategory(i.Category, i.Break, i.ID != 0 && overID.Load() == i.ID)
+
+		switch e.Type {
+		case pomodoro.EventStart, pomodoro.EventResume:
+			w.update([]int{}, i.Category, message, "", redrawCh)
+		case pomodoro.EventTick:
+			if i.OpenEnded() {
+				// Секундомер: бублик крутится по кругу раз в минуту
+				w.update([]int{int(i.ActualDuration % time.Minute), int(time.Minute)}, "",
+					" Работаем, пока работается.. жми Finish для перерыва ",
+					"Прошло "+formatDuration(i.ActualDuration), redrawCh)
+				return
+			}
+			if overtime := i.Overtime(); overtime > 0 {
+				w.update([]int{int(i.ActualDuration), int(i.PlannedDuration)}, "",
+					" Время вышло.. жми Finish для завершения ",
+					"Переработка +"+formatDuration(overtime), redrawCh)
+				return
+			}
+			w.update([]int{int(i.ActualDuration), int(i.PlannedDuration)}, "", "",
+				fmt.Sprint(i.PlannedDuration-i.ActualDuration), redrawCh)
+		case pomodoro.EventPause:
+			w.update([]int{}, "", " На паузе.. жми Start для продолжения ", "", redrawCh)
+		case pomodoro.EventDone:
+			w.update([]int{}, "", dailySummary(config), "", redrawCh)
+		case pomodoro.EventInterrupt:
+			w.update([]int{}, "", fmt.Sprintf(" Прерывание записано: внутренних %d, внешних %d ",
+				i.InterruptionCount(pomodoro.InterruptionInternal),
+				i.InterruptionCount(pomodoro.InterruptionExternal)), "", redrawCh)
+		case pomodoro.EventReset:
+			timer, left := []int{}, ""
+			if !i.OpenEnded() {
+				timer, left = []int{0, int(i.PlannedDuration)}, fmt.Sprint(i.PlannedDuration)
+			}
+			message := " Интервал начат заново.. жми Start "
+			if i.State == pomodoro.StateRunning {
+				message = " Интервал начат заново - с чистого листа "
+			}
+			w.update(timer, i.Category, message, left, redrawCh)
+		case pomodoro.EventCancel:
+			w.update([]int{}, "", " Интервал отменён.."+dailySummary(config), "", redrawCh)
+		case pomodoro.EventTask:
+			if overID.Load() != i.ID {
+				message = taskMessage(config, i)
+			}
+			w.update([]int{}, "", message, "", redrawCh)
+		}
+	}
+}
+
+// Сообщение о задаче, которую получил интервал
+func taskMessage(config *pomodoro.IntevalConfig, i pomodoro.Interval) string {
+	if i.Task == "" {
+		return " Задача снята "
+	}
+	if i.TaskID != 0 {
+		if p, err := pomodoro.TaskStatus(config, i.TaskID); err == nil {
+			return fmt.Sprintf(" Задача: %s, помидоров %s ", p.Title, p.Progress())
+		}
+	}
+	return " Задача: " + i.Task + " "
+}
+
+// Сообщение, если рабочий интервал выводит задачу за её оценку
+func overEstimate(config *pomodoro.IntevalConfig, i pomodoro.Interval) (string, bool) {
+	if i.Break || i.TaskID == 0 {
+		return "", false
+	}
+	p, err := pomodoro.TaskStatus(config, i.TaskID)
+	// Текущий интервал ещё не завершён и в факт не вошёл
+	if err != nil || p.Estimate == 0 || p.Actual+1 <= p.Estimate {
+		return "", false
+	}
+	return fmt.Sprintf(" Задача «%s» сверх оценки: помидор %d из %d ",
+		p.Title, p.Actual+1, p.Estimate), true
+}
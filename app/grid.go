@@ -1,7 +1,8 @@
 package app
 
 import (
-	"log"
+	"image"
+	"log/slog"
 
 	"github.com/mum4k/termdash/align"
 	"github.com/mum4k/termdash/container"
@@ -10,17 +11,73 @@ import (
 	"github.com/mum4k/termdash/terminal/terminalapi"
 )
 
-func newGrid(b *buttonsSet, w *widgets, t terminalapi.Terminal) (*container.Container, error) {
-	log.Println("newGrid")
+// Раскладки экрана - от полной к самой скромной
+const (
+	layoutFull = iota
+	layoutCompact
+	layoutMinimal
+)
+
+var layoutNames = map[int]string{
+	layoutFull:    "full",
+	layoutCompact: "compact",
+	layoutMinimal: "minimal",
+}
+
+// Наименьшие размеры терминала для раскладок. Меньше полной -
+// сегментный индикатор в узкой панели tmux уже не прочитать.
+var (
+	fullMinSize    = image.Point{X: 60, Y: 20}
+	compactMinSize = image.Point{X: 30, Y: 5}
+)
+
+// Выбирает раскладку по размеру терминала; compact - не больше компактной
+func chooseLayout(size image.Point, compact bool) int {
+	switch {
+	case !compact && size.X >= fullMinSize.X && size.Y >= fullMinSize.Y:
+		return layoutFull
+	case size.X >= compactMinSize.X && size.Y >= compactMinSize.Y:
+		return layoutCompact
+	default:
+		return layoutMinimal
+	}
+}
+
+// Собирает контейнер с раскладкой layout
+func newGrid(layout int, b *buttonsSet, w *widgets, keys Keymap, theme Theme,
+	t terminalapi.Terminal,
+) (*container.Container, error) {
+	slog.Debug("Сборка сетки TUI", "layout", layoutNames[layout])
+
+	var opts []container.Option
+	var err error
+	switch layout {
+	case layoutFull:
+		opts, err = fullLayout(b, w, keys, theme)
+	case layoutCompact:
+		opts = compactLayout(w, keys, theme)
+	default:
+		opts = minimalLayout(w)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return container.New(t, opts...)
+}
+
+// Полная раскладка: бублик, индикатор категории, информация и кнопки
+func fullLayout(b *buttonsSet, w *widgets, keys Keymap, theme Theme) ([]container.Option, error) {
 	builder := grid.New()
-	log.Println("builder created")
 
 	builder.Add(
 		grid.RowHeightPerc(40,
 			grid.ColWidthPercWithOpts(40,
 				[]container.Option{
 					container.Border(linestyle.Light),
-					container.BorderTitle("Жми Q для выхода"),
+					container.BorderColor(theme.Border),
+					container.FocusedColor(theme.Title),
+					container.TitleColor(theme.Title),
+					container.BorderTitle(keys.hint()),
 				},
 				// внутренняя строка
 				grid.RowHeightPerc(80,
@@ -39,10 +96,12 @@ func newGrid(b *buttonsSet, w *widgets, t terminalapi.Terminal) (*container.Cont
 
 			grid.ColWidthPerc(60,
 				grid.RowHeightPerc(70,
-					grid.Widget(w.disType, container.Border(linestyle.Light)),
+					grid.Widget(w.disType, container.Border(linestyle.Light),
+						container.BorderColor(theme.Border), container.FocusedColor(theme.Title)),
 				),
 				grid.RowHeightPerc(30,
-					grid.Widget(w.txtInfo, container.Border(linestyle.Light)),
+					grid.Widget(w.txtInfo, container.Border(linestyle.Light),
+						container.BorderColor(theme.Border), container.FocusedColor(theme.Title)),
 				),
 			),
 		),
@@ -50,21 +109,51 @@ func newGrid(b *buttonsSet, w *widgets, t terminalapi.Terminal) (*container.Cont
 
 	builder.Add(
 		grid.RowHeightPerc(20,
-			grid.ColWidthPerc(50, grid.Widget(b.btStart)),
-			grid.ColWidthPerc(50, grid.Widget(b.btPause)),
+			grid.ColWidthPerc(33, grid.Widget(b.btStart)),
+			grid.ColWidthPerc(33, grid.Widget(b.btPause)),
+			grid.ColWidthPerc(34, grid.Widget(b.btFinish)),
 		),
 	)
 
 	builder.Add(grid.RowHeightPerc(40))
 
-	gridOpts, err := builder.Build()
-	if err != nil {
-		return nil, err
+	return builder.Build()
+}
+
+// Компактная раскладка: строка с категорией и временем, под ней - информация.
+// Кнопок нет - работают клавиши.
+func compactLayout(w *widgets, keys Keymap, theme Theme) []container.Option {
+	return []container.Option{
+		container.Border(linestyle.Light),
+		container.BorderColor(theme.Border),
+		container.FocusedColor(theme.Title),
+		container.TitleColor(theme.Title),
+		container.BorderTitle(keys.hint()),
+		container.SplitHorizontal(
+			container.Top(timerLine(w)...),
+			container.Bottom(container.PlaceWidget(w.txtInfo)),
+			container.SplitFixed(1),
+		),
+	}
+}
+
+// Минимальная раскладка: только текст, без рамок - для совсем маленьких панелей
+func minimalLayout(w *widgets) []container.Option {
+	return []container.Option{
+		container.SplitHorizontal(
+			container.Top(timerLine(w)...),
+			container.Bottom(container.PlaceWidget(w.txtInfo)),
+			container.SplitFixed(1),
+		),
 	}
+}
 
-	c, err := container.New(t, gridOpts...)
-	if err != nil {
-		return nil, err
+// Строка таймера: категория слева, оставшееся время справа
+func timerLine(w *widgets) []container.Option {
+	return []container.Option{
+		container.SplitVertical(
+			container.Left(container.PlaceWidget(w.txtCategory)),
+			container.Right(container.PlaceWidget(w.txtTimer)),
+		),
 	}
-	return c, nil
 }
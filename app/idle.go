@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Настройки детектора простоя
+type IdleOptions struct {
+	// Через сколько напомнить о себе, если интервал не идёт (0 - не напоминать)
+	Nudge time.Duration
+	// Напоминать звонком терминала
+	Bell bool
+	// Команда-напоминание, исполняется через sh -c. В окружении POMO_IDLE_SECONDS -
+	// сколько простаивали, POMO_STATE - состояние текущего интервала
+	Hook string
+	// Через сколько без клавиатуры и мыши ставить рабочий интервал на паузу
+	// (0 - не ставить). Перерывы не трогаем - на перерыве от терминала и уходят.
+	Pause time.Duration
+}
+
+// Детектор простоя: помнит время последней активности - клавиши, мыши
+// или смены состояния интервала (кто бы его ни менял: TUI, API, истечение)
+type idle struct {
+	mu     sync.Mutex
+	last   time.Time
+	nudged bool
+}
+
+func newIdle() *idle {
+	return &idle{last: time.Now()}
+}
+
+// Отмечает активность - отсчёт простоя начинается заново
+func (d *idle) touch() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.last = time.Now()
+	d.nudged = false
+}
+
+// Подписчик на события интервалов: всё, кроме тиков, - активность
+func (d *idle) observe(e pomodoro.Event) {
+	if e.Type != pomodoro.EventTick {
+		d.touch()
+	}
+}
+
+// Сколько длится простой и напоминали ли уже о нём
+func (d *idle) state() (time.Duration, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return time.Since(d.last), d.nudged
+}
+
+func (d *idle) setNudged() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nudged = true
+}
+
+// Раз в секунду проверяет простой: напоминает, если интервал не идёт,
+// и ставит на паузу рабочий интервал, если за терминалом никого нет.
+// Напоминание - одно на каждый простой.
+func watchIdle(a *App, config *pomodoro.IntevalConfig, d *idle, opts IdleOptions) {
+	if opts.Nudge <= 0 && opts.Pause <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		i, err := config.Repository().Last()
+		if err != nil && !errors.Is(err, pomodoro.ErrNoIntervals) {
+			a.errorCh <- err
+			return
+		}
+		idleFor, nudged := d.state()
+
+		if i.State == pomodoro.StateRunning {
+			if opts.Pause > 0 && !i.Break && idleFor >= opts.Pause {
+				if err := i.Pause(config); err != nil && !errors.Is(err, pomodoro.ErrIntervalNotRunning) {
+					a.errorCh <- err
+					return
+				}
+				a.Notify(fmt.Sprintf("Никого нет уже %s - интервал на паузе", idleFor.Round(time.Minute)))
+			}
+			continue
+		}
+
+		if opts.Nudge > 0 && !nudged && idleFor >= opts.Nudge {
+			d.setNudged()
+			nudge(a.ctx, i, idleFor, opts)
+			a.Notify(fmt.Sprintf("Простаиваем уже %s.. жми Start", idleFor.Round(time.Minute)))
+		}
+	}
+}
+
+// Напоминает звонком терминала и командой-напоминанием
+func nudge(ctx context.Context, i pomodoro.Interval, idleFor time.Duration, opts IdleOptions) {
+	slog.Info("Простой", "idle", idleFor.Round(time.Second), "state", pomodoro.StateName(i.State))
+	if opts.Bell {
+		// Экраном владеет tcell, но BEL курсор не двигает - можно писать напрямую
+		os.Stdout.WriteString("\a")
+	}
+	if opts.Hook == "" {
+		return
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", opts.Hook)
+	cmd.Env = append(os.Environ(),
+		"POMO_IDLE_SECONDS="+strconv.Itoa(int(idleFor.Seconds())),
+		"POMO_STATE="+pomodoro.StateName(i.State),
+	)
+	go func() {
+		if out, err := cmd.CombinedOutput(); err != nil {
+			slog.Warn("Команда-напоминание о простое", "hook", opts.Hook, "error", err, "output", string(out))
+		}
+	}()
+}
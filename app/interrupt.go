@@ -0,0 +1,21 @@
+package app
+
+import (
+	"errors"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Записывает прерывание текущего интервала, не останавливая его.
+// Экран обновит подписчик display по событию interrupt.
+func interrupt(config *pomodoro.IntevalConfig, kind string, w *widgets,
+	redrawCh chan<- bool, errorCh chan<- error,
+) {
+	if _, err := pomodoro.Interrupt(config, kind, ""); err != nil {
+		if errors.Is(err, pomodoro.ErrIntervalNotRunning) {
+			w.update([]int{}, "", " Прерывать нечего - интервал не идёт ", "", redrawCh)
+			return
+		}
+		errorCh <- err
+	}
+}
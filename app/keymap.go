@@ -0,0 +1,228 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mum4k/termdash/keyboard"
+)
+
+var (
+	ErrUnknownAction = errors.New("неизвестное действие")
+	ErrInvalidKey    = errors.New("неверная клавиша")
+	ErrKeyConflict   = errors.New("клавиша назначена двум действиям")
+)
+
+// Действия TUI, которым назначаются клавиши
+const (
+	ActionStart             = "start"
+	ActionPause             = "pause"
+	ActionFinish            = "finish"
+	ActionSkip              = "skip"
+	ActionCancel            = "cancel"
+	ActionReset             = "reset"
+	ActionTask              = "task"
+	ActionPickTask          = "pick_task"
+	ActionInterruptInternal = "interrupt_internal"
+	ActionInterruptExternal = "interrupt_external"
+	ActionProfile           = "profile"
+	ActionHelp              = "help"
+	ActionQuit              = "quit"
+	ActionForceQuit         = "force_quit"
+)
+
+// Действие с описанием для справки
+type actionInfo struct {
+	name string
+	help string
+}
+
+// Действия в порядке показа в справке
+var actionList = []actionInfo{
+	{ActionStart, "Старт или продолжение интервала"},
+	{ActionPause, "Пауза"},
+	{ActionFinish, "Завершить интервал"},
+	{ActionSkip, "Пропустить интервал"},
+	{ActionCancel, "Отменить интервал"},
+	{ActionReset, "Начать интервал заново"},
+	{ActionTask, "Задача для интервалов - ввести текстом"},
+	{ActionPickTask, "Задача для интервалов - выбрать из списка"},
+	{ActionInterruptInternal, "Прерывание: отвлёкся сам"},
+	{ActionInterruptExternal, "Прерывание: отвлекли"},
+	{ActionProfile, "Следующий профиль"},
+	{ActionHelp, "Эта справка"},
+	{ActionQuit, "Выход - с вопросом, если интервал идёт"},
+	{ActionForceQuit, "Выход без вопросов - интервал отменится"},
+}
+
+// Раскладка клавиш: действие - клавиши.
+// Клавиша - символ (s, ?, +) или имя: space, enter, esc, tab, backspace,
+// up, down, left, right, home, end, pgup, pgdn, insert, delete, f1..f12,
+// ctrl+a..ctrl+z. Буквы - без учёта регистра.
+type Keymap map[string][]string
+
+// Раскладка по умолчанию
+func DefaultKeymap() Keymap {
+	return Keymap{
+		ActionStart:             {"s"},
+		ActionPause:             {"p"},
+		ActionFinish:            {"f"},
+		ActionSkip:              {"k"},
+		ActionCancel:            {"x"},
+		ActionReset:             {"r"},
+		ActionTask:              {"t"},
+		ActionPickTask:          {"l"},
+		ActionInterruptInternal: {"i"},
+		ActionInterruptExternal: {"e"},
+		ActionProfile:           {"n"},
+		ActionHelp:              {"?"},
+		ActionQuit:              {"q", "ctrl+c"},
+		ActionForceQuit:         {"ctrl+q"},
+	}
+}
+
+// Раскладка по умолчанию с заменой клавиш действий из overrides
+func (k Keymap) With(overrides Keymap) Keymap {
+	m := maps.Clone(k)
+	maps.Copy(m, overrides)
+	return m
+}
+
+// Клавиши по именам
+var keyNames = map[string]keyboard.Key{
+	"space":     keyboard.KeySpace,
+	"enter":     keyboard.KeyEnter,
+	"esc":       keyboard.KeyEsc,
+	"tab":       keyboard.KeyTab,
+	"backspace": keyboard.KeyBackspace2,
+	"up":        keyboard.KeyArrowUp,
+	"down":      keyboard.KeyArrowDown,
+	"left":      keyboard.KeyArrowLeft,
+	"right":     keyboard.KeyArrowRight,
+	"home":      keyboard.KeyHome,
+	"end":       keyboard.KeyEnd,
+	"pgup":      keyboard.KeyPgUp,
+	"pgdn":      keyboard.KeyPgDn,
+	"insert":    keyboard.KeyInsert,
+	"delete":    keyboard.KeyDelete,
+}
+
+func init() {
+	for n, k := range []keyboard.Key{
+		keyboard.KeyF1, keyboard.KeyF2, keyboard.KeyF3, keyboard.KeyF4,
+		keyboard.KeyF5, keyboard.KeyF6, keyboard.KeyF7, keyboard.KeyF8,
+		keyboard.KeyF9, keyboard.KeyF10, keyboard.KeyF11, keyboard.KeyF12,
+	} {
+		keyNames[fmt.Sprintf("f%d", n+1)] = k
+	}
+	// Ctrl+H, Ctrl+I и Ctrl+M терминал не отличает от Backspace, Tab и Enter
+	for n, k := range []keyboard.Key{
+		keyboard.KeyCtrlA, keyboard.KeyCtrlB, keyboard.KeyCtrlC, keyboard.KeyCtrlD,
+		keyboard.KeyCtrlE, keyboard.KeyCtrlF, keyboard.KeyCtrlG, keyboard.KeyCtrlH,
+		keyboard.KeyCtrlI, keyboard.KeyCtrlJ, keyboard.KeyCtrlK, keyboard.KeyCtrlL,
+		keyboard.KeyCtrlM, keyboard.KeyCtrlN, keyboard.KeyCtrlO, keyboard.KeyCtrlP,
+		keyboard.KeyCtrlQ, keyboard.KeyCtrlR, keyboard.KeyCtrlS, keyboard.KeyCtrlT,
+		keyboard.KeyCtrlU, keyboard.KeyCtrlV, keyboard.KeyCtrlW, keyboard.KeyCtrlX,
+		keyboard.KeyCtrlY, keyboard.KeyCtrlZ,
+	} {
+		keyNames["ctrl+"+string(rune('a'+n))] = k
+	}
+}
+
+// Разбирает клавишу. Буквы приводятся к нижнему регистру.
+func parseKey(s string) (keyboard.Key, error) {
+	if utf8.RuneCountInString(s) == 1 {
+		r, _ := utf8.DecodeRuneInString(s)
+		if unicode.IsPrint(r) {
+			return keyboard.Key(unicode.ToLower(r)), nil
+		}
+	}
+	if k, ok := keyNames[strings.ToLower(s)]; ok {
+		return k, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidKey, s)
+}
+
+// Раскладка, готовая к работе: клавиша - действие
+type bindings map[keyboard.Key]string
+
+// Проверяет раскладку: известные действия, верные клавиши, у клавиши
+// не больше одного действия. Возвращает клавиши с их действиями.
+func (k Keymap) compile() (bindings, error) {
+	b := bindings{}
+	// Обходим в порядке действий - чтобы ошибка была всегда одна и та же
+	for _, a := range slices.Sorted(maps.Keys(k)) {
+		if !slices.ContainsFunc(actionList, func(e actionInfo) bool { return e.name == a }) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownAction, a)
+		}
+		for _, s := range k[a] {
+			key, err := parseKey(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", a, err)
+			}
+			if other, ok := b[key]; ok && other != a {
+				return nil, fmt.Errorf("%w: %q - %s и %s", ErrKeyConflict, s, other, a)
+			}
+			b[key] = a
+		}
+	}
+	return b, nil
+}
+
+// Проверяет раскладку - для проверки конфига до запуска TUI
+func (k Keymap) Validate() error {
+	_, err := k.compile()
+	return err
+}
+
+// Действие клавиши; буквы - без учёта регистра
+func (b bindings) action(k keyboard.Key) (string, bool) {
+	if k >= 0 {
+		k = keyboard.Key(unicode.ToLower(rune(k)))
+	}
+	a, ok := b[k]
+	return a, ok
+}
+
+// Клавиши действия для подсказок: "s" или "q, ctrl+c"
+func (k Keymap) keys(action string) string {
+	return strings.Join(k[action], ", ")
+}
+
+// Текст справки: клавиши и действия
+func (k Keymap) help() string {
+	var sb strings.Builder
+	for _, a := range actionList {
+		keys := k.keys(a.name)
+		if keys == "" {
+			keys = "-"
+		}
+		fmt.Fprintf(&sb, " %-12s %s\n", keys, a.help)
+	}
+	return sb.String()
+}
+
+// Первая клавиша действия - для кнопок и заголовков; "" - клавиш нет
+func (k Keymap) key(action string) string {
+	if len(k[action]) == 0 {
+		return ""
+	}
+	return k[action][0]
+}
+
+// Подсказка для заголовка рамки: "? - клавиши, q - выход"
+func (k Keymap) hint() string {
+	var parts []string
+	if key := k.key(ActionHelp); key != "" {
+		parts = append(parts, key+" - клавиши")
+	}
+	if key := k.key(ActionQuit); key != "" {
+		parts = append(parts, key+" - выход")
+	}
+	return strings.Join(parts, ", ")
+}
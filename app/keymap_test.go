@@ -0,0 +1,92 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mum4k/termdash/keyboard"
+)
+
+func TestParseKey(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		expect keyboard.Key
+		expErr error
+	}{
+		{name: "Letter", input: "s", expect: 's'},
+		{name: "LetterUpper", input: "S", expect: 's'},
+		{name: "Cyrillic", input: "Ы", expect: 'ы'},
+		{name: "Symbol", input: "?", expect: '?'},
+		{name: "Name", input: "space", expect: keyboard.KeySpace},
+		{name: "NameCase", input: "Enter", expect: keyboard.KeyEnter},
+		{name: "Function", input: "f12", expect: keyboard.KeyF12},
+		{name: "Ctrl", input: "ctrl+q", expect: keyboard.KeyCtrlQ},
+		{name: "CtrlCase", input: "CTRL+C", expect: keyboard.KeyCtrlC},
+		// Терминал не отличает Ctrl+I от Tab
+		{name: "CtrlITab", input: "ctrl+i", expect: keyboard.KeyTab},
+		{name: "Empty", input: "", expErr: ErrInvalidKey},
+		{name: "Word", input: "ss", expErr: ErrInvalidKey},
+		{name: "CtrlDigit", input: "ctrl+1", expErr: ErrInvalidKey},
+		{name: "Function13", input: "f13", expErr: ErrInvalidKey},
+		{name: "Control", input: "\t", expErr: ErrInvalidKey},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			k, err := parseKey(tc.input)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("Ожидали ошибку: %v, а получили: %v", tc.expErr, err)
+			}
+			if err == nil && k != tc.expect {
+				t.Errorf("Ожидали клавишу: %v, а получили: %v", tc.expect, k)
+			}
+		})
+	}
+}
+
+func TestKeymapCompile(t *testing.T) {
+	testCases := []struct {
+		name      string
+		overrides Keymap
+		expErr    error
+		// Клавиша и ожидаемое действие; пустое - клавиша ничего не делает
+		key    keyboard.Key
+		action string
+	}{
+		{name: "Default", key: 'S', action: ActionStart},
+		{name: "Move", overrides: Keymap{ActionPause: {"space"}},
+			key: keyboard.KeySpace, action: ActionPause},
+		{name: "Freed", overrides: Keymap{ActionPause: {"space"}}, key: 'p'},
+		{name: "SameKeyTwice", overrides: Keymap{ActionStart: {"s", "S"}}, key: 's', action: ActionStart},
+		{name: "Unbound", overrides: Keymap{ActionHelp: {}}, key: '?'},
+		{name: "UnknownAction", overrides: Keymap{"jump": {"j"}}, expErr: ErrUnknownAction},
+		{name: "InvalidKey", overrides: Keymap{ActionStart: {"ctrl+alt+s"}}, expErr: ErrInvalidKey},
+		{name: "Conflict", overrides: Keymap{ActionStart: {"p"}}, expErr: ErrKeyConflict},
+		{name: "ConflictCase", overrides: Keymap{ActionStart: {"P"}}, expErr: ErrKeyConflict},
+		{name: "ConflictCtrlITab", overrides: Keymap{ActionTask: {"tab"}, ActionPickTask: {"ctrl+i"}},
+			expErr: ErrKeyConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			k := DefaultKeymap().With(tc.overrides)
+
+			b, err := k.compile()
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("Ожидали ошибку: %v, а получили: %v", tc.expErr, err)
+			}
+			if !errors.Is(k.Validate(), tc.expErr) {
+				t.Errorf("Validate должна вернуть то же, что compile: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			a, ok := b.action(tc.key)
+			if a != tc.action || ok != (tc.action != "") {
+				t.Errorf("Ожидали действие: %q, а получили: %q (%t)", tc.action, a, ok)
+			}
+		})
+	}
+}
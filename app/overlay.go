@@ -0,0 +1,104 @@
+package app
+
+import (
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/widgets/text"
+	"github.com/mum4k/termdash/widgets/textinput"
+)
+
+// Окна поверх раскладки: справка по клавишам, ввод задачи,
+// подтверждение выхода и выбор задачи из списка
+const (
+	overlayNone = iota
+	overlayHelp
+	overlayTask
+	overlayQuit
+	overlayTasks
+)
+
+// Просит Run показать окно поверх раскладки. Вызывается из обработчиков
+// клавиш, поэтому не блокируется: если прежний запрос ещё не обработан,
+// новый отбрасывается.
+func (a *App) showOverlay(overlay int) {
+	select {
+	case a.overlayCh <- overlay:
+	default:
+	}
+}
+
+// Справка по клавишам - текст из раскладки
+func newHelp(keys Keymap, theme Theme) (*text.Text, error) {
+	txt, err := text.New()
+	if err != nil {
+		return nil, err
+	}
+	return txt, txt.Write(keys.help(), text.WriteCellOpts(
+		cell.FgColor(theme.Text), cell.BgColor(theme.Background)))
+}
+
+// Собирает окно overlay вместо раскладки
+func (a *App) overlayLayout(overlay int) ([]container.Option, error) {
+	theme := a.opts.Theme
+	opts := []container.Option{
+		container.Border(linestyle.Light),
+		container.BorderColor(theme.Border),
+		container.FocusedColor(theme.Title),
+		container.TitleColor(theme.Title),
+	}
+
+	switch overlay {
+	case overlayHelp:
+		return append(opts,
+			container.BorderTitle("Клавиши - любая закроет справку"),
+			container.PlaceWidget(a.help),
+		), nil
+	case overlayQuit:
+		txt, err := text.New()
+		if err != nil {
+			return nil, err
+		}
+		if err := txt.Write(a.quitText(), text.WriteCellOpts(
+			cell.FgColor(theme.Text), cell.BgColor(theme.Background))); err != nil {
+			return nil, err
+		}
+		return append(opts,
+			container.BorderTitle("Выход"),
+			container.PlaceWidget(txt),
+		), nil
+	case overlayTasks:
+		txt, err := a.picker.widget(theme)
+		if err != nil {
+			return nil, err
+		}
+		return append(opts,
+			container.BorderTitle("Задачи: стрелки - выбор, Enter - привязать, Esc - закрыть"),
+			container.PlaceWidget(txt),
+		), nil
+	}
+
+	// Поле ввода каждый раз новое - с действующей задачей
+	input, err := textinput.New(
+		textinput.Label("Задача: ", cell.FgColor(theme.Title)),
+		textinput.DefaultText(a.config.ActiveTask()),
+		textinput.PlaceHolder("пусто - без задачи"),
+		textinput.FillColor(theme.Background),
+		textinput.TextColor(theme.Text),
+		textinput.OnSubmit(func(task string) error {
+			go setTask(a.config, task, a.w, a.redrawCh, a.errorCh)
+			a.showOverlay(overlayNone)
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return append(opts,
+		container.BorderTitle("Enter - сохранить, Esc - отмена"),
+		container.AlignVertical(align.VerticalTop),
+		container.PlaceWidget(input),
+		container.Focused(),
+	), nil
+}
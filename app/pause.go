@@ -0,0 +1,51 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Следит за ограничением паузы, пока работает TUI. Первая проверка - сразу
+// при запуске: интервал мог остаться на паузе с прошлого раза. Дальше раз
+// в секунду показывает в информационной панели, сколько ещё можно стоять
+// на паузе, а по истечении отменяет интервал или начинает его заново.
+func watchPause(a *App, config *pomodoro.IntevalConfig) {
+	if config.MaxPause <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		i, expired, err := pomodoro.ExpirePause(config)
+		if err != nil {
+			a.errorCh <- err
+			return
+		}
+
+		if expired {
+			// display уже показал отмену или сброс - уточняем причину
+			what := "отменён"
+			if config.PauseAction == pomodoro.PauseActionReset {
+				what = "начат заново"
+			}
+			a.Notify(fmt.Sprintf("Пауза дольше %s - интервал %s", config.MaxPause, what))
+		} else if left, ok := config.PauseLeft(i, time.Now()); ok {
+			what := "отмена"
+			if config.PauseAction == pomodoro.PauseActionReset {
+				what = "сброс"
+			}
+			a.Notify(fmt.Sprintf("На паузе.. жми Start для продолжения, %s через %s",
+				what, formatDuration(left)))
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/widgets/text"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Выбор задачи из списка задач: открытые задачи, курсор на одной из них
+type taskPicker struct {
+	mu     sync.Mutex
+	tasks  []pomodoro.TaskProgress
+	cursor int
+	// Задача, привязанная сейчас, - помечается в списке
+	active int64
+	txt    *text.Text
+}
+
+// Загружает открытые задачи и показывает окно выбора
+func (a *App) openPicker() {
+	list, err := pomodoro.TaskList(a.config, false)
+	if errors.Is(err, pomodoro.ErrNoTasks) {
+		a.Notify("Список задач не поддерживается хранилищем")
+		return
+	}
+	if err != nil {
+		a.errorCh <- err
+		return
+	}
+	if len(list) == 0 {
+		a.Notify("Задач нет - добавь: pomo task add НАЗВАНИЕ")
+		return
+	}
+
+	p := a.picker
+	p.mu.Lock()
+	p.tasks, p.cursor, p.active = list, 0, a.config.ActiveTaskID()
+	for k, t := range list {
+		if t.ID == p.active {
+			p.cursor = k
+		}
+	}
+	p.mu.Unlock()
+	a.showOverlay(overlayTasks)
+}
+
+// Новый текстовый виджет для окна выбора
+func (p *taskPicker) widget(theme Theme) (*text.Text, error) {
+	txt, err := text.New()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.txt = txt
+	return txt, p.render(theme)
+}
+
+// Двигает курсор на delta строк, по кругу
+func (p *taskPicker) move(delta int, theme Theme) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.tasks) == 0 {
+		return nil
+	}
+	p.cursor = (p.cursor + delta + len(p.tasks)) % len(p.tasks)
+	return p.render(theme)
+}
+
+// Задача под курсором
+func (p *taskPicker) selected() (pomodoro.TaskProgress, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.tasks) == 0 {
+		return pomodoro.TaskProgress{}, false
+	}
+	return p.tasks[p.cursor], true
+}
+
+// Перерисовывает список. Вызывать под блокировкой.
+func (p *taskPicker) render(theme Theme) error {
+	if p.txt == nil {
+		return nil
+	}
+	p.txt.Reset()
+	for k, t := range p.tasks {
+		cursor, active := " ", " "
+		if k == p.cursor {
+			cursor = ">"
+		}
+		if t.ID == p.active {
+			active = "*"
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, " %s%s #%-3d %s", cursor, active, t.ID, t.Title)
+		if t.Project != "" {
+			fmt.Fprintf(&sb, " [%s]", t.Project)
+		}
+		fmt.Fprintf(&sb, "  %s\n", t.Progress())
+
+		opts := []cell.Option{cell.FgColor(theme.Text), cell.BgColor(theme.Background)}
+		if k == p.cursor {
+			opts = []cell.Option{cell.FgColor(theme.Title), cell.BgColor(theme.Background), cell.Bold()}
+		}
+		if err := p.txt.Write(sb.String(), text.WriteCellOpts(opts...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"errors"
+	"slices"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Переключает на следующий профиль по кругу. Сменить профиль можно
+// только между интервалами - исполняющийся дорабатывает по старому.
+func nextProfile(config *pomodoro.IntevalConfig, w *widgets,
+	redrawCh chan<- bool, errorCh chan<- error,
+) {
+	profiles := config.Profiles()
+	if len(profiles) < 2 {
+		w.update([]int{}, "", " Других профилей нет - опиши их в конфиг-файле ", "", redrawCh)
+		return
+	}
+
+	active := config.ActiveProfile()
+	k := slices.IndexFunc(profiles, func(p pomodoro.Profile) bool { return p.Name == active })
+	next := profiles[(k+1)%len(profiles)].Name
+
+	if err := pomodoro.SwitchProfile(config, next); err != nil {
+		if errors.Is(err, pomodoro.ErrIntervalActive) {
+			w.update([]int{}, "", " Профиль можно сменить только между интервалами ", "", redrawCh)
+			return
+		}
+		errorCh <- err
+		return
+	}
+	w.update([]int{}, "", " Профиль: "+next+" ", "", redrawCh)
+}
@@ -0,0 +1,133 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Что сделать с исполняющимся интервалом при выходе
+const (
+	QuitPause      = "pause"
+	QuitCancel     = "cancel"
+	QuitBackground = "background"
+)
+
+var ErrInvalidQuitAction = errors.New("неизвестное действие при выходе")
+
+// Настройки выхода из TUI
+type QuitOptions struct {
+	// Выбор по умолчанию в окне подтверждения - по Enter
+	Default string
+	// Запускает фоновый процесс, который доведёт интервал до конца.
+	// nil - фонового режима нет, и в окне его не предлагаем.
+	Background func() error
+}
+
+// Проверяет действие при выходе
+func ValidateQuitAction(action string) error {
+	switch action {
+	case QuitPause, QuitCancel, QuitBackground:
+		return nil
+	}
+	return fmt.Errorf("%w: %q - допустимо %s, %s или %s",
+		ErrInvalidQuitAction, action, QuitPause, QuitCancel, QuitBackground)
+}
+
+// Выход по клавише. Если интервал идёт - сначала спрашиваем, что с ним
+// сделать: без вопроса выход его бы отменил.
+func (a *App) quit() {
+	i, err := a.config.Repository().Last()
+	if err != nil && !errors.Is(err, pomodoro.ErrNoIntervals) {
+		a.errorCh <- err
+		return
+	}
+	if err != nil || i.State != pomodoro.StateRunning {
+		a.cancel()
+		return
+	}
+	a.showOverlay(overlayQuit)
+}
+
+// Выход с выбранным действием над исполняющимся интервалом
+func (a *App) quitWith(action string) {
+	i, err := a.config.Repository().Last()
+	if err != nil && !errors.Is(err, pomodoro.ErrNoIntervals) {
+		a.errorCh <- err
+		return
+	}
+
+	switch action {
+	case QuitPause:
+		err = i.Pause(a.config)
+	case QuitCancel:
+		// Отменяем сами, а не ждём tick(): процесс может завершиться раньше
+		err = i.Cancel(a.config)
+	case QuitBackground:
+		// Фоновый процесс продолжит с того, что записано в репозитории
+		if err := a.config.SaveProgress(); err != nil {
+			a.errorCh <- err
+			return
+		}
+		if err := a.opts.Quit.Background(); err != nil {
+			a.showOverlay(overlayNone)
+			a.Notify("Фоновый режим не запустился: " + err.Error())
+			return
+		}
+		// Контекст не отменяем - иначе tick() отменит интервал.
+		// Его подхватит фоновый процесс.
+		select {
+		case a.detachCh <- struct{}{}:
+		default:
+		}
+		return
+	}
+	// Интервал мог успеть завершиться, пока открыто окно, - это не ошибка
+	if err != nil && !errors.Is(err, pomodoro.ErrIntervalNotRunning) &&
+		!errors.Is(err, pomodoro.ErrIntervalCompleted) {
+		a.errorCh <- err
+		return
+	}
+	a.cancel()
+}
+
+// Клавиша выбора в окне выхода; Enter - выбор по умолчанию
+func (a *App) quitChoice(r rune) (string, bool) {
+	switch r {
+	case 'p', 'P':
+		return QuitPause, true
+	case 'c', 'C':
+		return QuitCancel, true
+	case 'b', 'B':
+		return QuitBackground, a.opts.Quit.Background != nil
+	}
+	return "", false
+}
+
+// Текст окна выхода
+func (a *App) quitText() string {
+	choices := []struct {
+		key, action, text string
+	}{
+		{"p", QuitPause, "поставить на паузу и выйти"},
+		{"c", QuitCancel, "отменить и выйти"},
+		{"b", QuitBackground, "оставить идти в фоне и выйти"},
+	}
+
+	var sb strings.Builder
+	sb.WriteString(" Интервал ещё идёт. Что с ним сделать?\n\n")
+	for _, c := range choices {
+		if c.action == QuitBackground && a.opts.Quit.Background == nil {
+			continue
+		}
+		fmt.Fprintf(&sb, " %s - %s", c.key, c.text)
+		if c.action == a.opts.Quit.Default {
+			sb.WriteString(" (Enter)")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(" Esc - не выходить\n")
+	return sb.String()
+}
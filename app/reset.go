@@ -0,0 +1,29 @@
+package app
+
+import (
+	"errors"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Начинает текущий интервал заново. Экран обновит подписчик display
+// по событию reset, исполняющийся интервал продолжит тикать с нуля.
+func reset(config *pomodoro.IntevalConfig, w *widgets,
+	redrawCh chan<- bool, errorCh chan<- error,
+) {
+	// Только последний интервал: GetInterval создал бы новый ради того,
+	// чтобы сбрасывать было нечего
+	i, err := config.Repository().Last()
+	if err == nil {
+		err = i.Reset(config)
+	}
+	switch {
+	case err == nil:
+	case errors.Is(err, pomodoro.ErrNoIntervals),
+		errors.Is(err, pomodoro.ErrIntervalNotRunning),
+		errors.Is(err, pomodoro.ErrIntervalCompleted):
+		w.update([]int{}, "", " Сбрасывать нечего - интервал не начат или уже закончен ", "", redrawCh)
+	default:
+		errorCh <- err
+	}
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Пропускает текущий интервал - в том числе ещё не начатый -
+// и показывает следующий по расписанию
+func skip(config *pomodoro.IntevalConfig, w *widgets,
+	redrawCh chan<- bool, errorCh chan<- error,
+) {
+	next, err := pomodoro.Skip(config)
+	if err != nil {
+		errorCh <- err
+		return
+	}
+
+	timer, left := []int{}, ""
+	if !next.OpenEnded() {
+		timer, left = []int{0, int(next.PlannedDuration)}, fmt.Sprint(next.PlannedDuration)
+	}
+	w.setCategory(next.Category, next.Break, false)
+	w.update(timer, next.Category, " Пропущено.. следующий: "+next.Category+", жми Start ", left, redrawCh)
+}
+
+// Отменяет исполняющийся или приостановленный интервал.
+// Экран обновит подписчик display по событию cancel.
+func cancelInterval(config *pomodoro.IntevalConfig, w *widgets,
+	redrawCh chan<- bool, errorCh chan<- error,
+) {
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		errorCh <- err
+		return
+	}
+	if err := i.Cancel(config); err != nil {
+		if errors.Is(err, pomodoro.ErrIntervalNotRunning) {
+			w.update([]int{}, "", " Отменять нечего - интервал ещё не начат ", "", redrawCh)
+			return
+		}
+		errorCh <- err
+	}
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Меняет задачу текущего и следующих интервалов
+func setTask(config *pomodoro.IntevalConfig, task string, w *widgets,
+	redrawCh chan<- bool, errorCh chan<- error,
+) {
+	task = strings.TrimSpace(task)
+	i, err := pomodoro.SetTask(config, task)
+	if err != nil {
+		errorCh <- err
+		return
+	}
+	if assigned(i) {
+		return
+	}
+
+	message := " Задача: " + task + " "
+	if task == "" {
+		message = " Задача снята "
+	}
+	w.update([]int{}, "", message, "", redrawCh)
+}
+
+// Привязывает текущий и следующие интервалы к задаче из списка
+func attachTask(config *pomodoro.IntevalConfig, t pomodoro.TaskProgress, w *widgets,
+	redrawCh chan<- bool, errorCh chan<- error,
+) {
+	i, err := pomodoro.AttachTask(config, t.ID)
+	if err != nil {
+		if errors.Is(err, pomodoro.ErrTaskDone) {
+			// Задачу успели отметить выполненной из другого терминала
+			w.update([]int{}, "", " "+err.Error()+" ", "", redrawCh)
+			return
+		}
+		errorCh <- err
+		return
+	}
+	if assigned(i) {
+		return
+	}
+	w.update([]int{}, "", fmt.Sprintf(" Задача: %s, помидоров %s ", t.Title, t.Progress()), "", redrawCh)
+}
+
+// Задачу получил сам текущий интервал - тогда о ней уже сообщил display
+// по событию task, заодно проверив, не вышла ли она за оценку.
+// Иначе задача достанется только следующим интервалам.
+func assigned(i pomodoro.Interval) bool {
+	return i.ID != 0 && i.State != pomodoro.StateDone && i.State != pomodoro.StateCancelled
+}
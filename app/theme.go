@@ -0,0 +1,214 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mum4k/termdash/cell"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+var (
+	ErrUnknownTheme = errors.New("неизвестная тема")
+	ErrInvalidColor = errors.New("неверный цвет")
+)
+
+// Тема по умолчанию
+const DefaultTheme = "dark"
+
+// Цветовая тема TUI
+type Theme struct {
+	Name string
+	// Фон и текст всего экрана, рамки и их заголовки
+	Background cell.Color
+	Text       cell.Color
+	Border     cell.Color
+	Title      cell.Color
+	// Бублик и индикатор категории: цвет категории, если он задан,
+	// иначе - рабочий или перерыва. При переработке - Overtime.
+	Work       cell.Color
+	Break      cell.Color
+	Overtime   cell.Color
+	Categories map[string]cell.Color
+	// Кнопки
+	Start      cell.Color
+	Pause      cell.Color
+	Finish     cell.Color
+	ButtonText cell.Color
+}
+
+// Встроенные темы. Категории раскрашены по-разному, чтобы работу
+// от перерыва было видно через всю комнату.
+func Themes() []Theme {
+	return []Theme{
+		{
+			Name:       "dark",
+			Background: cell.ColorDefault,
+			Text:       cell.ColorDefault,
+			Border:     cell.ColorNumber(245),
+			Title:      cell.ColorNumber(250),
+			Work:       cell.ColorNumber(203),
+			Break:      cell.ColorNumber(114),
+			Overtime:   cell.ColorNumber(170),
+			Categories: map[string]cell.Color{
+				pomodoro.CategoryPomodoro:   cell.ColorNumber(203),
+				pomodoro.CategoryShortBreak: cell.ColorNumber(114),
+				pomodoro.CategoryLongBreak:  cell.ColorNumber(75),
+			},
+			Start:      cell.ColorNumber(66),
+			Pause:      cell.ColorNumber(220),
+			Finish:     cell.ColorNumber(66),
+			ButtonText: cell.ColorBlack,
+		},
+		{
+			// Для светлого фона - тёмные насыщенные цвета
+			Name:       "light",
+			Background: cell.ColorDefault,
+			Text:       cell.ColorDefault,
+			Border:     cell.ColorNumber(243),
+			Title:      cell.ColorNumber(238),
+			Work:       cell.ColorNumber(160),
+			Break:      cell.ColorNumber(28),
+			Overtime:   cell.ColorNumber(127),
+			Categories: map[string]cell.Color{
+				pomodoro.CategoryPomodoro:   cell.ColorNumber(160),
+				pomodoro.CategoryShortBreak: cell.ColorNumber(28),
+				pomodoro.CategoryLongBreak:  cell.ColorNumber(25),
+			},
+			Start:      cell.ColorNumber(31),
+			Pause:      cell.ColorNumber(136),
+			Finish:     cell.ColorNumber(31),
+			ButtonText: cell.ColorWhite,
+		},
+		{
+			// Чистые цвета на чёрном - без полутонов
+			Name:       "high-contrast",
+			Background: cell.ColorBlack,
+			Text:       cell.ColorWhite,
+			Border:     cell.ColorWhite,
+			Title:      cell.ColorYellow,
+			Work:       cell.ColorRed,
+			Break:      cell.ColorLime,
+			Overtime:   cell.ColorFuchsia,
+			Categories: map[string]cell.Color{
+				pomodoro.CategoryPomodoro:   cell.ColorRed,
+				pomodoro.CategoryShortBreak: cell.ColorLime,
+				pomodoro.CategoryLongBreak:  cell.ColorAqua,
+			},
+			Start:      cell.ColorWhite,
+			Pause:      cell.ColorYellow,
+			Finish:     cell.ColorWhite,
+			ButtonText: cell.ColorBlack,
+		},
+	}
+}
+
+// Встроенная тема по имени
+func BuiltinTheme(name string) (Theme, error) {
+	for _, t := range Themes() {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return Theme{}, fmt.Errorf("%w: %q", ErrUnknownTheme, name)
+}
+
+// Цвет категории интервала
+func (t Theme) Category(category string, isBreak bool) cell.Color {
+	if c, ok := t.Categories[category]; ok {
+		return c
+	}
+	if isBreak {
+		return t.Break
+	}
+	return t.Work
+}
+
+// Ключи цветов темы в конфиге
+var themeColors = map[string]func(*Theme) *cell.Color{
+	"background":  func(t *Theme) *cell.Color { return &t.Background },
+	"text":        func(t *Theme) *cell.Color { return &t.Text },
+	"border":      func(t *Theme) *cell.Color { return &t.Border },
+	"title":       func(t *Theme) *cell.Color { return &t.Title },
+	"work":        func(t *Theme) *cell.Color { return &t.Work },
+	"break":       func(t *Theme) *cell.Color { return &t.Break },
+	"overtime":    func(t *Theme) *cell.Color { return &t.Overtime },
+	"start":       func(t *Theme) *cell.Color { return &t.Start },
+	"pause":       func(t *Theme) *cell.Color { return &t.Pause },
+	"finish":      func(t *Theme) *cell.Color { return &t.Finish },
+	"button_text": func(t *Theme) *cell.Color { return &t.ButtonText },
+}
+
+// Задаёт цвет темы по ключу конфига (text, border, work, ...)
+func (t *Theme) SetColor(key, value string) error {
+	f, ok := themeColors[key]
+	if !ok {
+		return fmt.Errorf("%w: неизвестный ключ %q, допустимо: %s", ErrInvalidColor, key,
+			strings.Join(slices.Sorted(maps.Keys(themeColors)), ", "))
+	}
+	c, err := ParseColor(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	*f(t) = c
+	return nil
+}
+
+// Задаёт цвет категории интервала
+func (t *Theme) SetCategory(category, value string) error {
+	c, err := ParseColor(value)
+	if err != nil {
+		return fmt.Errorf("категория %s: %w", category, err)
+	}
+	// Карту копируем - она общая с темой, от которой эту унаследовали
+	categories := maps.Clone(t.Categories)
+	if categories == nil {
+		categories = map[string]cell.Color{}
+	}
+	categories[category] = c
+	t.Categories = categories
+	return nil
+}
+
+// Цвета по именам - 16 цветов xterm
+var colorNames = map[string]cell.Color{
+	"default": cell.ColorDefault,
+	"black":   cell.ColorBlack,
+	"maroon":  cell.ColorMaroon,
+	"green":   cell.ColorGreen,
+	"olive":   cell.ColorOlive,
+	"navy":    cell.ColorNavy,
+	"purple":  cell.ColorPurple,
+	"teal":    cell.ColorTeal,
+	"silver":  cell.ColorSilver,
+	"gray":    cell.ColorGray,
+	"red":     cell.ColorRed,
+	"lime":    cell.ColorLime,
+	"yellow":  cell.ColorYellow,
+	"blue":    cell.ColorBlue,
+	"fuchsia": cell.ColorFuchsia,
+	"aqua":    cell.ColorAqua,
+	"white":   cell.ColorWhite,
+}
+
+// Разбирает цвет: имя (red, navy, default), номер из палитры 256 цветов
+// или #rrggbb - он приводится к ближайшему цвету палитры
+func ParseColor(s string) (cell.Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if c, ok := colorNames[s]; ok {
+		return c, nil
+	}
+	if hex, ok := strings.CutPrefix(s, "#"); ok && len(hex) == 6 {
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			return cell.ColorRGB24(int(v>>16), int(v>>8&0xff), int(v&0xff)), nil
+		}
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return cell.ColorNumber(n), nil
+	}
+	return cell.ColorDefault, fmt.Errorf("%w: %q - допустимо имя, номер 0..255 или #rrggbb", ErrInvalidColor, s)
+}
@@ -0,0 +1,141 @@
+package app_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mum4k/termdash/cell"
+	"vegorov.ru/go-cli/pomo/app"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestParseColor(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		expect cell.Color
+		expErr error
+	}{
+		{name: "Name", input: "navy", expect: cell.ColorNavy},
+		{name: "NameCase", input: " Red ", expect: cell.ColorRed},
+		{name: "Default", input: "default", expect: cell.ColorDefault},
+		{name: "Number", input: "203", expect: cell.ColorNumber(203)},
+		{name: "NumberZero", input: "0", expect: cell.ColorNumber(0)},
+		{name: "Hex", input: "#ff8800", expect: cell.ColorRGB24(0xff, 0x88, 0x00)},
+		{name: "HexCase", input: "#FF8800", expect: cell.ColorRGB24(0xff, 0x88, 0x00)},
+		{name: "NumberTooBig", input: "256", expErr: app.ErrInvalidColor},
+		{name: "NumberNegative", input: "-1", expErr: app.ErrInvalidColor},
+		{name: "HexShort", input: "#f80", expErr: app.ErrInvalidColor},
+		{name: "HexNotHex", input: "#gg8800", expErr: app.ErrInvalidColor},
+		{name: "Unknown", input: "orange", expErr: app.ErrInvalidColor},
+		{name: "Empty", input: "", expErr: app.ErrInvalidColor},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := app.ParseColor(tc.input)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("Ожидали ошибку: %v, а получили: %v", tc.expErr, err)
+			}
+			if err == nil && c != tc.expect {
+				t.Errorf("Ожидали цвет: %v, а получили: %v", tc.expect, c)
+			}
+		})
+	}
+}
+
+func TestSetColor(t *testing.T) {
+	testCases := []struct {
+		name   string
+		key    string
+		value  string
+		get    func(app.Theme) cell.Color
+		expect cell.Color
+		expErr error
+		// Ошибка должна называть ключ или допустимые ключи
+		expMsg string
+	}{
+		{name: "Work", key: "work", value: "blue",
+			get: func(t app.Theme) cell.Color { return t.Work }, expect: cell.ColorBlue},
+		{name: "ButtonText", key: "button_text", value: "#000000",
+			get: func(t app.Theme) cell.Color { return t.ButtonText }, expect: cell.ColorRGB24(0, 0, 0)},
+		{name: "UnknownKey", key: "shadow", value: "blue", expErr: app.ErrInvalidColor, expMsg: "button_text"},
+		{name: "BadValue", key: "border", value: "blurple", expErr: app.ErrInvalidColor, expMsg: "border"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			theme, err := app.BuiltinTheme(app.DefaultTheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = theme.SetColor(tc.key, tc.value)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("Ожидали ошибку: %v, а получили: %v", tc.expErr, err)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tc.expMsg) {
+					t.Errorf("Ожидали в ошибке %q, а получили: %v", tc.expMsg, err)
+				}
+				return
+			}
+			if c := tc.get(theme); c != tc.expect {
+				t.Errorf("Ожидали цвет: %v, а получили: %v", tc.expect, c)
+			}
+		})
+	}
+}
+
+func TestSetCategory(t *testing.T) {
+	base, err := app.BuiltinTheme(app.DefaultTheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		theme    app.Theme
+		category string
+		value    string
+		expErr   error
+	}{
+		{name: "Builtin", theme: base, category: pomodoro.CategoryPomodoro, value: "blue"},
+		{name: "Custom", theme: base, category: "DeepWork", value: "57"},
+		{name: "NoCategories", theme: app.Theme{}, category: "DeepWork", value: "57"},
+		{name: "BadValue", theme: base, category: "DeepWork", value: "#12", expErr: app.ErrInvalidColor},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			theme := tc.theme
+			before := theme.Category(tc.category, false)
+
+			err := theme.SetCategory(tc.category, tc.value)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("Ожидали ошибку: %v, а получили: %v", tc.expErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			expect, _ := app.ParseColor(tc.value)
+			if c := theme.Category(tc.category, false); c != expect {
+				t.Errorf("Ожидали цвет категории: %v, а получили: %v", expect, c)
+			}
+			// Тема, от которой унаследовали, не меняется
+			if c := tc.theme.Category(tc.category, false); c != before {
+				t.Errorf("Исходная тема изменилась: %v вместо %v", c, before)
+			}
+		})
+	}
+
+	// Категория без своего цвета - цвет работы или перерыва
+	if c := base.Category("Reading", true); c != base.Break {
+		t.Errorf("Ожидали цвет перерыва: %v, а получили: %v", base.Break, c)
+	}
+	if c := base.Category("Reading", false); c != base.Work {
+		t.Errorf("Ожидали цвет работы: %v, а получили: %v", base.Work, c)
+	}
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"sync"
 
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/widgets/donut"
@@ -10,14 +11,41 @@ import (
 )
 
 type widgets struct {
-	donTimer       *donut.Donut
-	disType        *segmentdisplay.SegmentDisplay
-	txtInfo        *text.Text
-	txtTimer       *text.Text
+	donTimer *donut.Donut
+	disType  *segmentdisplay.SegmentDisplay
+	txtInfo  *text.Text
+	txtTimer *text.Text
+	// Категория текстом - для раскладок без сегментного индикатора
+	txtCategory    *text.Text
 	updateDonTimer chan []int
 	upateTxtInfo   chan string
 	updateTxtTimer chan string
 	updateTxtType  chan string
+
+	theme Theme
+	// Цвет категории текущего интервала - для бублика и индикатора
+	mu    sync.Mutex
+	color cell.Color
+}
+
+// Запоминает категорию текущего интервала - по ней красятся бублик и индикатор.
+// over - задача интервала вышла за оценку, красим в цвет переработки.
+func (w *widgets) setCategory(category string, isBreak, over bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if over {
+		w.color = w.theme.Overtime
+		return
+	}
+	w.color = w.theme.Category(category, isBreak)
+}
+
+func (w *widgets) categoryColor() cell.Color {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.color
 }
 
 func (w *widgets) update(timer []int, txtType, txtInfo, txtTimer string, redrawCh chan<- bool) {
@@ -40,8 +68,8 @@ func (w *widgets) update(timer []int, txtType, txtInfo, txtTimer string, redrawC
 	redrawCh <- true
 }
 
-func newWidgets(ctx context.Context, errorCh chan<- error) (*widgets, error) {
-	w := &widgets{}
+func newWidgets(ctx context.Context, theme Theme, errorCh chan<- error) (*widgets, error) {
+	w := &widgets{theme: theme, color: theme.Work}
 	var err error
 
 	w.updateDonTimer = make(chan []int)
@@ -49,22 +77,26 @@ func newWidgets(ctx context.Context, errorCh chan<- error) (*widgets, error) {
 	w.updateTxtTimer = make(chan string)
 	w.updateTxtType = make(chan string)
 
-	w.donTimer, err = newDonut(ctx, w.updateDonTimer, errorCh)
+	w.donTimer, err = newDonut(ctx, w, errorCh)
 	if err != nil {
 		return nil, err
 	}
 
-	w.disType, err = newSegmentDisplay(ctx, w.updateTxtType, errorCh)
+	if w.txtCategory, err = text.New(); err != nil {
+		return nil, err
+	}
+
+	w.disType, err = newSegmentDisplay(ctx, w, errorCh)
 	if err != nil {
 		return nil, err
 	}
 
-	w.txtInfo, err = newText(ctx, w.upateTxtInfo, errorCh)
+	w.txtInfo, err = newText(ctx, w.upateTxtInfo, theme, errorCh)
 	if err != nil {
 		return nil, err
 	}
 
-	w.txtTimer, err = newText(ctx, w.updateTxtTimer, errorCh)
+	w.txtTimer, err = newText(ctx, w.updateTxtTimer, theme, errorCh)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +104,9 @@ func newWidgets(ctx context.Context, errorCh chan<- error) (*widgets, error) {
 	return w, err
 }
 
-func newText(ctx context.Context, updateText <-chan string, errorCh chan<- error) (*text.Text, error) {
+func newText(ctx context.Context, updateText <-chan string, theme Theme,
+	errorCh chan<- error,
+) (*text.Text, error) {
 	txt, err := text.New()
 	if err != nil {
 		return nil, err
@@ -84,7 +118,8 @@ func newText(ctx context.Context, updateText <-chan string, errorCh chan<- error
 			select {
 			case t := <-updateText:
 				txt.Reset()
-				errorCh <- txt.Write(t)
+				errorCh <- txt.Write(t, text.WriteCellOpts(
+					cell.FgColor(theme.Text), cell.BgColor(theme.Background)))
 			case <-ctx.Done():
 				return
 			}
@@ -94,8 +129,10 @@ func newText(ctx context.Context, updateText <-chan string, errorCh chan<- error
 	return txt, nil
 }
 
-func newDonut(ctx context.Context, donUpdater <-chan []int, errorCh chan<- error) (*donut.Donut, error) {
-	don, err := donut.New(donut.Clockwise(), donut.CellOpts(cell.FgColor(cell.ColorBlue)))
+func newDonut(ctx context.Context, w *widgets, errorCh chan<- error) (*donut.Donut, error) {
+	don, err := donut.New(donut.Clockwise(),
+		donut.CellOpts(cell.FgColor(w.categoryColor())),
+		donut.TextCellOpts(cell.FgColor(w.theme.Text)))
 	if err != nil {
 		return nil, err
 	}
@@ -103,10 +140,13 @@ func newDonut(ctx context.Context, donUpdater <-chan []int, errorCh chan<- error
 	go func() {
 		for {
 			select {
-			case d := <-donUpdater:
+			case d := <-w.updateDonTimer:
 				if d[0] <= d[1] {
-					errorCh <- don.Absolute(d[0], d[1])
+					errorCh <- don.Absolute(d[0], d[1], donut.CellOpts(cell.FgColor(w.categoryColor())))
+					continue
 				}
+				// Переработка - бублик заполнен целиком и меняет цвет
+				errorCh <- don.Absolute(d[1], d[1], donut.CellOpts(cell.FgColor(w.theme.Overtime)))
 			case <-ctx.Done():
 				return
 			}
@@ -115,7 +155,7 @@ func newDonut(ctx context.Context, donUpdater <-chan []int, errorCh chan<- error
 	return don, nil
 }
 
-func newSegmentDisplay(ctx context.Context, updateText <-chan string,
+func newSegmentDisplay(ctx context.Context, w *widgets,
 	errorCh chan<- error,
 ) (*segmentdisplay.SegmentDisplay, error) {
 	sd, err := segmentdisplay.New()
@@ -126,13 +166,21 @@ func newSegmentDisplay(ctx context.Context, updateText <-chan string,
 	go func() {
 		for {
 			select {
-			case t := <-updateText:
+			case t := <-w.updateTxtType:
 				if t == "" {
 					t = " "
 				}
-				errorCh <- sd.Write([]*segmentdisplay.TextChunk{
-					segmentdisplay.NewChunk(t),
-				})
+				color := w.categoryColor()
+				if err := sd.Write([]*segmentdisplay.TextChunk{
+					segmentdisplay.NewChunk(t, segmentdisplay.WriteCellOpts(
+						cell.FgColor(color), cell.BgColor(w.theme.Background))),
+				}); err != nil {
+					errorCh <- err
+					continue
+				}
+				w.txtCategory.Reset()
+				errorCh <- w.txtCategory.Write(t, text.WriteCellOpts(
+					cell.FgColor(color), cell.BgColor(w.theme.Background), cell.Bold()))
 			case <-ctx.Done():
 				return
 			}
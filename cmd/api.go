@@ -0,0 +1,143 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/viper"
+	"vegorov.ru/go-cli/pomo/api"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Сколько ждать ответа HTTP API запущенного pomo
+const apiCallTimeout = 5 * time.Second
+
+// Запрос до pomo не дошёл: API выключен или никто не слушает порт -
+// действие надо выполнить самим
+var errAPIDown = errors.New("HTTP API не запущен")
+
+// Настройки HTTP API из конфига и флагов
+func apiOptions() (api.Options, error) {
+	opts := api.Options{
+		Port:  viper.GetInt("api.port"),
+		Token: viper.GetString("api.token"),
+	}
+	if opts.Port < 1 || opts.Port > 65535 {
+		return opts, fmt.Errorf("%w: api.port %d вне 1..65535", pomodoro.ErrInvalidConfig, opts.Port)
+	}
+	return opts, nil
+}
+
+// Занимает порт HTTP API, если он включён; nil - API выключен.
+// Порт занимаем до запуска TUI - чтобы о занятом порте сообщить сразу,
+// а не в информационной панели.
+func listenAPI() (net.Listener, error) {
+	if !viper.GetBool("api.enabled") {
+		return nil, nil
+	}
+
+	opts, err := apiOptions()
+	if err != nil {
+		return nil, err
+	}
+	return net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", opts.Port))
+}
+
+// Обслуживает HTTP API на ln вместе с TUI или фоновым serve: интервалы,
+// запущенные через API, исполняются в контексте ctx, ошибки уходят в notify -
+// у TUI это информационная панель
+func serveAPI(ctx context.Context, notify func(string), config *pomodoro.IntevalConfig, ln net.Listener) {
+	if ln == nil {
+		return
+	}
+
+	opts, _ := apiOptions()
+	opts.OnError = func(err error) {
+		slog.Error("API: интервал", "error", err)
+		notify("API: " + err.Error())
+	}
+
+	s := api.New(ctx, config, opts)
+	go func() {
+		if err := s.Serve(ln); err != nil {
+			slog.Error("API остановлен", "error", err)
+			notify("API остановлен: " + err.Error())
+		}
+	}()
+}
+
+// Выполняет запрос к HTTP API запущенного pomo - TUI или фонового serve -
+// и разбирает ответ в v. errAPIDown - API выключен в конфиге или не запущен.
+func callAPI(method, path string, body, v any) error {
+	if !viper.GetBool("api.enabled") {
+		return errAPIDown
+	}
+	opts, err := apiOptions()
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, fmt.Sprintf("http://127.0.0.1:%d%s", opts.Port, path), &b)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.Token)
+	}
+
+	client := http.Client{Timeout: apiCallTimeout}
+	res, err := client.Do(req)
+	// Не подключились - запрос точно не выполнен, повторять его самим безопасно
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return errAPIDown
+	}
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&e); err != nil || e.Error == "" {
+			return fmt.Errorf("HTTP API: %s", res.Status)
+		}
+		return fmt.Errorf("HTTP API: %s", e.Error)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
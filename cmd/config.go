@@ -0,0 +1,399 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Источники значений настроек - в порядке убывания приоритета
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceFile    = "file"
+	sourceDefault = "default"
+)
+
+// Настройки верхнего уровня, которые показывает config show
+var configKeys = []string{
+	"profile", "pomo", "short", "long", "overtime", "mode",
+	"pause.max", "pause.action",
+	"idle.nudge", "idle.bell", "idle.hook", "idle.pause", "theme", "themes", "compact", "keys", "quit.action",
+	"flowtime", "types", "sequence", "profiles",
+	"api.enabled", "api.port", "api.token", "webhooks",
+	"log.level", "log.format", "log.file",
+}
+
+// Флаги, имя которых не совпадает с ключом настройки
+var flagNames = map[string]string{
+	"pause.max":    "max-pause",
+	"pause.action": "pause-action",
+	"api.enabled":  "api",
+	"api.port":     "api-port",
+	"log.level":    "log-level",
+	"log.format":   "log-format",
+	"log.file":     "log-file",
+}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Просмотр, проверка и создание конфиг-файла",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Действующие настройки и источник каждого значения",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return configShowAction(os.Stdout)
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Проверить конфиг-файл и настройки",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return configValidateAction(os.Stdout)
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Создать конфиг-файл с комментариями",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+		return configInitAction(os.Stdout, force)
+	},
+}
+
+func init() {
+	configInitCmd.Flags().Bool("force", false, "Перезаписать существующий файл")
+
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configInitCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// Откуда взято значение ключа: флаг, переменная окружения, файл или умолчание
+func valueSource(key string) string {
+	name := key
+	if n, ok := flagNames[key]; ok {
+		name = n
+	}
+	if f := rootCmd.PersistentFlags().Lookup(name); f != nil && f.Changed {
+		return sourceFlag
+	}
+	if f := rootCmd.Flags().Lookup(name); f != nil && f.Changed {
+		return sourceFlag
+	}
+	// viper.AutomaticEnv ищет переменную с именем ключа в верхнем регистре
+	if _, ok := os.LookupEnv(strings.ToUpper(key)); ok {
+		return sourceEnv
+	}
+	if viper.InConfig(key) {
+		return sourceFile
+	}
+	return sourceDefault
+}
+
+func configShowAction(out io.Writer) error {
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		file = "не найден"
+	}
+	fmt.Fprintf(out, "Конфиг-файл: %s\n\n", file)
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "КЛЮЧ\tЗНАЧЕНИЕ\tИСТОЧНИК")
+	for _, key := range configKeys {
+		value := fmt.Sprint(viper.Get(key))
+		switch key {
+		case "profile":
+			value = activeProfile()
+		case "pomo", "short", "long", "pause.max", "idle.nudge", "idle.pause":
+			value = viper.GetDuration(key).String()
+		case "sequence":
+			value = "-"
+			if viper.IsSet(key) {
+				value = strings.Join(viper.GetStringSlice(key), ", ")
+			}
+		case "log.level", "log.file", "idle.hook":
+			if value == "" {
+				value = "-"
+			}
+		case "api.token":
+			// Сам токен не показываем
+			value = "-"
+			if viper.GetString(key) != "" {
+				value = "задан"
+			}
+		case "flowtime", "types", "profiles", "webhooks", "themes", "keys":
+			// Составные значения целиком не показываем - только есть ли они
+			if viper.IsSet(key) {
+				value = "задано"
+			} else {
+				value = "-"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", key, value, valueSource(key))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	profiles, err := readProfiles()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "\nПрофили:")
+	active := activeProfile()
+	for _, p := range profiles {
+		mark := " "
+		if p.Name == active {
+			mark = "*"
+		}
+		fmt.Fprintf(out, "%s %-12s %s\n", mark, p.Name, describeProfile(p))
+	}
+	return nil
+}
+
+func configValidateAction(out io.Writer) error {
+	if path := viper.ConfigFileUsed(); path != "" {
+		if err := checkConfigFile(path); err != nil {
+			return err
+		}
+	}
+
+	profiles, err := readProfiles()
+	if err != nil {
+		return err
+	}
+	if err := pomodoro.ValidateProfiles(profiles); err != nil {
+		return err
+	}
+
+	// Выбранный профиль должен существовать
+	config := pomodoro.NewConfig(nil, 0, 0, 0)
+	config.SetProfiles(profiles)
+	if err := config.UseProfile(activeProfile()); err != nil {
+		return err
+	}
+
+	if _, _, err := readPauseLimit(); err != nil {
+		return err
+	}
+	if _, err := tuiOptions(); err != nil {
+		return err
+	}
+	if _, err := apiOptions(); err != nil {
+		return err
+	}
+	if _, err := readWebhooks(); err != nil {
+		return err
+	}
+	if _, err := readLogOptions(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Настройки в порядке, профилей: %d\n", len(profiles))
+	return nil
+}
+
+// Проверяет, что конфиг-файл читается и разбирается. viper при ошибке
+// разбора в initConfig и при слежении за файлом молча оставляет прежние
+// настройки - поэтому проверяем файл отдельным экземпляром viper.
+func checkConfigFile(path string) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	return v.ReadInConfig()
+}
+
+func configInitAction(out io.Writer, force bool) error {
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%w: %s (используй --force для перезаписи)", os.ErrExist, path)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if err := os.WriteFile(path, []byte(starterConfig), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Создан конфиг-файл: %s\n", path)
+	return nil
+}
+
+// Стартовый конфиг-файл: все настройки со значениями по умолчанию и пояснениями
+const starterConfig = `# Конфиг-файл pomo
+# Продолжительности - в формате Go: 25m, 1h30m, 90s
+
+# Действующий профиль (см. profiles ниже); переключить - pomo profile use NAME
+# profile: default
+
+# Режим работы: classic - фиксированное расписание,
+# flowtime - работа до естественной остановки, перерыв пропорционален работе
+mode: classic
+
+# Продолжительности классического расписания (от 1m до 4h для работы,
+# от 1m до 2h для перерывов)
+pomo: 25m
+short: 5m
+long: 15m
+
+# Режим переработки: интервал не завершается сам по истечении времени,
+# а считает переработку, пока не нажмёшь Finish
+overtime: false
+
+# Максимальная пауза: интервал, простоявший на паузе дольше, отменяется
+# (action: cancel) или начинается заново (action: reset). 0 - без ограничения.
+# pause:
+#   max: 0s
+#   action: cancel
+
+# Простой в TUI. nudge - через сколько напомнить, если интервал не идёт:
+# звонком терминала (bell) и/или командой hook (sh -c, в окружении
+# POMO_IDLE_SECONDS и POMO_STATE). pause - через сколько без клавиатуры
+# и мыши ставить рабочий интервал на паузу. 0 - выключено.
+# idle:
+#   nudge: 0s
+#   bell: true
+#   hook: notify-send "pomo" "Пора за работу"
+#   pause: 0s
+
+# Раскладка TUI выбирается по размеру терминала: полная, компактная
+# (строка с таймером) или минимальная (только текст). compact: true -
+# не больше компактной, даже в большом окне (или флаг --compact).
+compact: false
+
+# Цветовая тема TUI: dark, light, high-contrast или собственная из themes.
+# Собственная тема берёт цвета встроенной (base) и заменяет часть из них.
+# Цвет - имя (red, navy, default), номер 0..255 или #rrggbb.
+# Ключи colors: background, text, border, title, work, break, overtime,
+# start, pause, finish, button_text. categories - цвета категорий интервалов.
+theme: dark
+# themes:
+#   - name: solarized
+#     base: light
+#     colors:
+#       border: "#93a1a1"
+#     categories:
+#       - name: DeepWork
+#         color: navy
+
+# Клавиши TUI: действие - клавиша или список клавиш. Заданное действие
+# получает только свои клавиши, остальные остаются по умолчанию.
+# Клавиша - символ или имя: space, enter, esc, tab, backspace, up, down,
+# left, right, home, end, pgup, pgdn, insert, delete, f1..f12, ctrl+a..ctrl+z.
+# Буквы - без учёта регистра. Одна клавиша - не больше чем на одно действие.
+# Действия: start, pause, finish, skip, cancel, reset, task, pick_task,
+# interrupt_internal, interrupt_external, profile, help, quit, force_quit.
+# В TUI справка по клавишам - "?".
+# keys:
+#   start: [s, space]
+#   skip: k
+#   help: "?"
+#   quit: [q, ctrl+c]
+#   force_quit: ctrl+q
+
+# Выход из TUI, пока интервал идёт, - с вопросом, что с интервалом сделать:
+# pause - поставить на паузу, cancel - отменить, background - оставить идти
+# в фоне (его доведёт pomo serve). action - выбор по умолчанию, по Enter.
+# force_quit выходит без вопросов, интервал при этом отменяется.
+quit:
+  action: pause
+
+# Таблица перерывов для flowtime: работа до up_to - перерыв ratio от неё.
+# Строка без up_to - для работы любой длины, она должна быть последней.
+# flowtime:
+#   - up_to: 25m
+#     ratio: 0.2
+#   - up_to: 50m
+#     ratio: 0.16
+#   - up_to: 90m
+#     ratio: 0.11
+#   - ratio: 0.17
+
+# Собственное расписание: типы интервалов и их последовательность по кругу.
+# Типы Pomodoro, ShortBreak и LongBreak доступны без описания.
+# types:
+#   - name: DeepWork
+#     duration: 50m
+#   - name: Review
+#     duration: 15m
+#     break: true
+# sequence: [DeepWork, ShortBreak, DeepWork, ShortBreak, DeepWork, ShortBreak, DeepWork, Review]
+
+# Именованные профили. Незаданные значения берутся из настроек выше.
+# profiles:
+#   - name: coding
+#     pomo: 50m
+#     short: 10m
+#     long: 30m
+#   - name: meetings
+#     mode: flowtime
+
+# HTTP API на localhost для плагинов редакторов и скриптов (или флаг --api).
+# Там же поток событий /api/v1/events (SSE) и метрики /metrics для Prometheus.
+# Если задан token, запросы должны нести заголовок Authorization: Bearer <token>
+# api:
+#   enabled: false
+#   port: 7465
+#   token: ""
+
+# Вебхуки: POST JSON на адрес при событиях интервала - start, resume, pause,
+# done, cancel, interrupt, reset (events не задан - все). С secret тело подписывается:
+# X-Pomo-Signature: sha256=<hex HMAC-SHA256>. Неотправленное повторяется
+# с нарастающей задержкой и переживает перезапуск.
+# webhooks:
+#   - url: https://chat.example/hooks/pomo
+#     events: [start, done]
+#     secret: s3cret
+
+# Лог: уровень debug, info, warn или error (по умолчанию info для TUI,
+# warn для команд), формат text или json.
+# Файл по умолчанию: пока работает TUI - $XDG_STATE_HOME/pomo/pomo.log,
+# у остальных команд - stderr. Значение stderr - всегда в stderr.
+# log:
+#   level: ""
+#   format: text
+#   file: ""
+`
@@ -0,0 +1,138 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/export"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Выгрузить историю интервалов в CSV, JSON, JSON Lines или iCalendar",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := filterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		ics := export.ICSOptions{}
+		if ics.Cancelled, err = cmd.Flags().GetBool("cancelled"); err != nil {
+			return err
+		}
+		if ics.UIDDomain, err = cmd.Flags().GetString("uid-domain"); err != nil {
+			return err
+		}
+
+		repo, err := getRepo()
+		if err != nil {
+			return err
+		}
+
+		out := io.Writer(os.Stdout)
+		if output != "" && output != "-" {
+			file, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			out = file
+		}
+		return exportAction(out, repo, format, f, ics)
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringP("format", "f", export.FormatCSV,
+		"Формат: "+strings.Join(export.Formats, ", "))
+	exportCmd.Flags().String("out", "", "Файл для выгрузки (по умолчанию - stdout)")
+	exportCmd.Flags().Bool("cancelled", false, "iCalendar: выгружать и отменённые интервалы")
+	exportCmd.Flags().String("uid-domain", export.DefaultUIDDomain,
+		"iCalendar: домен в UID событий - свой для каждого, если календари сводят вместе")
+	addFilterFlags(exportCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+func exportAction(out io.Writer, repo pomodoro.Repository, format string,
+	f pomodoro.Filter, ics export.ICSOptions,
+) error {
+	intervals, err := pomodoro.Query(repo, f)
+	if err != nil {
+		return err
+	}
+	if format == export.FormatICS {
+		return export.ICS(out, intervals, ics)
+	}
+	return export.Write(out, format, intervals)
+}
+
+// Флаги фильтра интервалов - общие для выгрузки и отчётов
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("from", "", "Начиная с даты (2006-01-02 или RFC 3339)")
+	cmd.Flags().String("to", "", "По дату включительно (2006-01-02 или RFC 3339)")
+	cmd.Flags().StringSlice("category", nil, "Только эти категории (можно несколько)")
+	cmd.Flags().StringSlice("tag", nil, "Только с одной из меток (можно несколько)")
+}
+
+// Собирает фильтр из флагов addFilterFlags
+func filterFromFlags(cmd *cobra.Command) (pomodoro.Filter, error) {
+	f := pomodoro.Filter{}
+
+	from, err := cmd.Flags().GetString("from")
+	if err != nil {
+		return f, err
+	}
+	if f.From, err = pomodoro.ParseDate(from, false); err != nil {
+		return f, err
+	}
+
+	to, err := cmd.Flags().GetString("to")
+	if err != nil {
+		return f, err
+	}
+	if f.To, err = pomodoro.ParseDate(to, true); err != nil {
+		return f, err
+	}
+
+	if f.Categories, err = cmd.Flags().GetStringSlice("category"); err != nil {
+		return f, err
+	}
+	if f.Tags, err = cmd.Flags().GetStringSlice("tag"); err != nil {
+		return f, err
+	}
+	return f, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/importer"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import FILE...",
+	Short: "Импортировать историю интервалов из других таймеров",
+	Long: `Импортирует интервалы из файлов (- означает stdin).
+
+Форматы:
+  json         - наша выгрузка pomo export (JSON или JSON Lines)
+  csv          - CSV с заголовком; колонки задаются через --map, например
+                 --map start=Date,duration=Minutes,task=Title
+                 (поля: start, end, duration, planned, category, break,
+                 state, profile, task, tags)
+  timewarrior  - файлы данных Timewarrior (~/.timewarrior/data/*.data)
+
+Интервалы, которые уже есть в истории (то же время начала и категория),
+пропускаются.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		opts := importer.Options{}
+		if opts.Mapping, err = cmd.Flags().GetStringToString("map"); err != nil {
+			return err
+		}
+		if opts.Category, err = cmd.Flags().GetString("category"); err != nil {
+			return err
+		}
+		tz, err := cmd.Flags().GetString("tz")
+		if err != nil {
+			return err
+		}
+		if tz != "" {
+			if opts.Location, err = time.LoadLocation(tz); err != nil {
+				return err
+			}
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		imp, err := importer.New(format, opts)
+		if err != nil {
+			return err
+		}
+
+		repo, err := getRepo()
+		if err != nil {
+			return err
+		}
+		return importAction(cmd.OutOrStdout(), repo, imp, args, dryRun)
+	},
+}
+
+func init() {
+	importCmd.Flags().StringP("format", "f", "json",
+		"Формат: "+strings.Join(importer.Formats(), ", "))
+	importCmd.Flags().StringToString("map", nil, "Соответствие полей колонкам CSV: поле=колонка")
+	importCmd.Flags().String("category", "", "Категория для интервалов без категории (по умолчанию Pomodoro)")
+	importCmd.Flags().String("tz", "", "Часовой пояс для времени без пояса (по умолчанию - локальный)")
+	importCmd.Flags().Bool("dry-run", false, "Только показать, что будет импортировано")
+	rootCmd.AddCommand(importCmd)
+}
+
+func importAction(out io.Writer, repo pomodoro.Repository, imp importer.Importer,
+	files []string, dryRun bool,
+) error {
+	// Сначала разбираем все файлы - чтобы ошибка в одном из них
+	// не оставила историю импортированной наполовину
+	var intervals []pomodoro.Interval
+	for _, name := range files {
+		parsed, err := parseFile(imp, name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		intervals = append(intervals, parsed...)
+	}
+
+	res, err := importer.Import(repo, intervals, dryRun)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		for _, i := range res.Imported {
+			fmt.Fprintf(out, "%s  %-12s %8s  %s\n", i.StartTime.Local().Format("2006-01-02 15:04"),
+				i.Category, i.ActualDuration.Round(time.Second), i.Task)
+		}
+		fmt.Fprintf(out, "Будет импортировано: %d из %d, дубликатов: %d (пробный запуск)\n",
+			len(res.Imported), res.Total, res.Duplicates)
+		return nil
+	}
+	fmt.Fprintf(out, "Импортировано: %d из %d, дубликатов: %d\n",
+		len(res.Imported), res.Total, res.Duplicates)
+	return nil
+}
+
+func parseFile(imp importer.Importer, name string) ([]pomodoro.Interval, error) {
+	if name == "-" {
+		return imp.Parse(os.Stdin)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return imp.Parse(f)
+}
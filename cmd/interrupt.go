@@ -0,0 +1,104 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"vegorov.ru/go-cli/pomo/api"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// interruptCmd represents the interrupt command
+var interruptCmd = &cobra.Command{
+	Use:   "interrupt [NOTE...]",
+	Short: "Записать прерывание текущего интервала, не останавливая его",
+	Long: `Записывает прерывание исполняющегося или приостановленного интервала -
+например, из pomo в соседнем терминале или по горячей клавише рабочего стола.
+
+Виды прерываний:
+  internal - отвлёкся сам (по умолчанию)
+  external - отвлекли другие
+
+Если в конфиг-файле включён HTTP API (api.enabled), прерывание уходит
+запущенному pomo - TUI или фоновому serve: он сразу покажет его и отправит
+вебхук interrupt. Без API прерывание записывается прямо в историю - TUI
+увидит его со следующим изменением интервала, но события interrupt
+не будет: ни на экране, ни в вебхуках.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, err := cmd.Flags().GetString("kind")
+		if err != nil {
+			return err
+		}
+
+		note := strings.Join(args, " ")
+
+		var res api.InterruptResponse
+		err = callAPI(http.MethodPost, "/api/v1/interval/interrupt",
+			api.InterruptRequest{Kind: kind, Note: note}, &res)
+		if err == nil {
+			printInterrupt(os.Stdout, res.Category, res.ID, res.Internal, res.External)
+			return nil
+		}
+		if !errors.Is(err, errAPIDown) {
+			return err
+		}
+
+		repo, err := getRepo()
+		if err != nil {
+			return err
+		}
+		config, err := newIntervalConfig(repo)
+		if err != nil {
+			return err
+		}
+		return interruptAction(os.Stdout, config, kind, note)
+	},
+}
+
+func init() {
+	interruptCmd.Flags().StringP("kind", "k", pomodoro.InterruptionInternal,
+		"Вид прерывания: internal или external")
+	rootCmd.AddCommand(interruptCmd)
+}
+
+func interruptAction(out io.Writer, config *pomodoro.IntevalConfig, kind, note string) error {
+	i, err := pomodoro.Interrupt(config, kind, note)
+	if err != nil {
+		return err
+	}
+	printInterrupt(out, i.Category, i.ID,
+		i.InterruptionCount(pomodoro.InterruptionInternal),
+		i.InterruptionCount(pomodoro.InterruptionExternal))
+	return nil
+}
+
+func printInterrupt(out io.Writer, category string, id int64, internal, external int) {
+	fmt.Fprintf(out, "Записано прерывание интервала %s #%d: внутренних %d, внешних %d\n",
+		category, id, internal, external)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Форматы лога
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// Вывод лога в stderr - вместо файла
+const logStderr = "stderr"
+
+// Закрывает файл лога при выходе
+var logCloser io.Closer
+
+// Настройки лога из конфига и флагов
+type logOptions struct {
+	level slog.Level
+	// Уровень задан явно - иначе у команд в stderr пишем только warn и выше,
+	// чтобы лог не мешался с их выводом
+	levelSet bool
+	format   string
+	file     string
+}
+
+func readLogOptions() (logOptions, error) {
+	opts := logOptions{
+		format: strings.ToLower(viper.GetString("log.format")),
+		file:   viper.GetString("log.file"),
+	}
+	level := viper.GetString("log.level")
+	if level == "" {
+		return opts, checkLogFormat(opts)
+	}
+	opts.levelSet = true
+	if err := opts.level.UnmarshalText([]byte(level)); err != nil {
+		return opts, fmt.Errorf("%w: log.level %q - допустимо debug, info, warn, error",
+			pomodoro.ErrInvalidConfig, level)
+	}
+	return opts, checkLogFormat(opts)
+}
+
+func checkLogFormat(opts logOptions) error {
+	if opts.format != logFormatText && opts.format != logFormatJSON {
+		return fmt.Errorf("%w: log.format %q - допустимо text или json",
+			pomodoro.ErrInvalidConfig, opts.format)
+	}
+	return nil
+}
+
+// Настраивает slog по умолчанию. Пока работает TUI, stderr занят экраном
+// termdash, а у фонового serve его нет вовсе, поэтому у них лог
+// по умолчанию пишется в $XDG_STATE_HOME/pomo/pomo.log;
+// у остальных команд - в stderr.
+func setupLogging(tui bool) error {
+	opts, err := readLogOptions()
+	if err != nil {
+		return err
+	}
+
+	file := opts.file
+	if file == "" && tui {
+		if file, err = xdgPath("XDG_STATE_HOME", ".local/state", "pomo.log"); err != nil {
+			return err
+		}
+	}
+
+	if !opts.levelSet && !tui {
+		opts.level = slog.LevelWarn
+	}
+
+	out := io.Writer(os.Stderr)
+	if file != "" && file != logStderr {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		logCloser = f
+		out = f
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: opts.level}
+	var h slog.Handler = slog.NewTextHandler(out, handlerOpts)
+	if opts.format == logFormatJSON {
+		h = slog.NewJSONHandler(out, handlerOpts)
+	}
+	slog.SetDefault(slog.New(h))
+	return nil
+}
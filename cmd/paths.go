@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// Путь к файлу name в каталоге pomo по спецификации XDG:
+// $env/pomo/name, а если переменная не задана - ~/fallback/pomo/name
+func xdgPath(env, fallback, name string) (string, error) {
+	base := os.Getenv(env)
+	if base == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, fallback)
+	}
+	return filepath.Join(base, "pomo", name), nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Профили настроек интервалов",
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Список профилей из конфиг-файла",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return profileListAction(os.Stdout)
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use NAME",
+	Short: "Сделать профиль действующим по умолчанию",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return profileUseAction(os.Stdout, args[0])
+	},
+}
+
+func init() {
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	rootCmd.AddCommand(profileCmd)
+}
+
+func profileListAction(out io.Writer) error {
+	profiles, err := readProfiles()
+	if err != nil {
+		return err
+	}
+
+	active := activeProfile()
+	for _, p := range profiles {
+		mark := " "
+		if p.Name == active {
+			mark = "*"
+		}
+		fmt.Fprintf(out, "%s %-12s %s\n", mark, p.Name, describeProfile(p))
+	}
+	return nil
+}
+
+// Краткое описание профиля для списка
+func describeProfile(p pomodoro.Profile) string {
+	var b strings.Builder
+	switch {
+	case p.Mode == pomodoro.ModeFlowtime:
+		b.WriteString("flowtime")
+	case len(p.Schedule.Sequence) > 0:
+		b.WriteString(strings.Join(p.Schedule.Sequence, "-"))
+	default:
+		fmt.Fprintf(&b, "%v/%v/%v", p.PomodoroDuration, p.ShortBreakDuration, p.LongBreakDuration)
+	}
+	if p.Overtime {
+		b.WriteString(", overtime")
+	}
+	return b.String()
+}
+
+func profileUseAction(out io.Writer, name string) error {
+	profiles, err := readProfiles()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, p := range profiles {
+		found = found || p.Name == name
+	}
+	if !found {
+		return fmt.Errorf("%w: %q", pomodoro.ErrUnknownProfile, name)
+	}
+
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+	if err := setConfigValue(path, "profile", name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Действующий профиль: %s (%s)\n", name, path)
+	return nil
+}
+
+// Путь к конфиг-файлу: прочитанный viper, заданный флагом или ~/.pomo.yaml
+func configFilePath() (string, error) {
+	if f := viper.ConfigFileUsed(); f != "" {
+		return f, nil
+	}
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".pomo.yaml"), nil
+}
+
+// Записывает в YAML-файл ключ верхнего уровня со скалярным значением.
+// Файл правим построчно, а не через viper.WriteConfig - чтобы
+// не потерять комментарии и не записать в файл значения флагов.
+func setConfigValue(path, key, value string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	line := fmt.Sprintf("%s: %s", key, value)
+	re := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(key) + `:.*$`)
+
+	if re.Match(data) {
+		data = re.ReplaceAllLiteral(data, []byte(line))
+	} else {
+		data = append([]byte(line+"\n"), data...)
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,37 @@
+//go:build !inmemory
+
+package cmd
+
+import (
+	"github.com/spf13/viper"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/repository"
+)
+
+// История в файле видна другим процессам pomo - интервал можно
+// оставить в фоне и доработать его в pomo serve
+const sharedRepo = true
+
+// Хранилище по умолчанию - JSON-файл. export, report, import и другие
+// команды запускаются отдельным процессом: с историей в памяти им нечего
+// было бы выгрузить или посчитать. Сборка с тегом inmemory оставляет
+// хранилище в памяти - для тестов и отладки.
+func getRepo() (pomodoro.Repository, error) {
+	path, err := dbPath()
+	if err != nil {
+		return nil, err
+	}
+	repo, err := repository.NewJSONFileRepo(path)
+	if err != nil {
+		return nil, err
+	}
+	return repository.WithLogging(repo), nil
+}
+
+// Путь к файлу с историей интервалов: из --db или в каталоге данных XDG
+func dbPath() (string, error) {
+	if path := viper.GetString("db"); path != "" {
+		return path, nil
+	}
+	return xdgPath("XDG_DATA_HOME", ".local/share", "pomo.json")
+}
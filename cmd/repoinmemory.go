@@ -1,3 +1,5 @@
+//go:build inmemory
+
 package cmd
 
 import (
@@ -5,6 +7,9 @@ import (
 	"vegorov.ru/go-cli/pomo/pomodoro/repository"
 )
 
+// История в памяти другим процессам не видна - фонового режима нет
+const sharedRepo = false
+
 func getRepo() (pomodoro.Repository, error) {
-	return repository.NewInMemoryRepo(), nil
+	return repository.WithLogging(repository.NewInMemoryRepo()), nil
 }
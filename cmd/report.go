@@ -0,0 +1,92 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Сводка по интервалам за период (по умолчанию - за сегодня)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := filterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		// Без дат - отчёт за сегодня
+		if f.From.IsZero() && f.To.IsZero() {
+			now := time.Now()
+			f.From = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+			f.To = f.From.AddDate(0, 0, 1)
+		}
+
+		repo, err := getRepo()
+		if err != nil {
+			return err
+		}
+		return reportAction(os.Stdout, repo, f)
+	},
+}
+
+func init() {
+	addFilterFlags(reportCmd)
+	rootCmd.AddCommand(reportCmd)
+}
+
+func reportAction(out io.Writer, repo pomodoro.Repository, f pomodoro.Filter) error {
+	intervals, err := pomodoro.Query(repo, f)
+	if err != nil {
+		return err
+	}
+	r := pomodoro.NewReport(intervals)
+	if len(r.Categories) == 0 {
+		fmt.Fprintln(out, "Интервалов за период нет")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "КАТЕГОРИЯ\tВСЕГО\tЗАВЕРШЕНО\tПО ПЛАНУ\tПЕРЕРАБОТКА\tПРЕРЫВАНИЯ ВНУТР/ВНЕШ\tПАУЗЫ")
+	row := func(name string, c pomodoro.CategoryReport) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%d/%d\t%d на %s\n", name, c.Count, c.Done,
+			c.Planned.Round(time.Minute), c.Overtime.Round(time.Minute), c.Internal, c.External,
+			c.Pauses, c.Paused.Round(time.Minute))
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Categories)) {
+		row(name, r.Categories[name])
+	}
+	fmt.Fprintln(tw, "\t\t\t\t\t\t")
+	row("Работа", r.Work)
+	row("Перерывы", r.Breaks)
+	return tw.Flush()
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// resetCmd represents the reset command
+var resetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Начать текущий интервал заново - с полной продолжительностью",
+	Long: `Сбрасывает отработанное время текущего интервала.
+
+Исполняющийся интервал продолжает идти с нуля - в том числе в TUI,
+запущенном в соседнем терминале. Приостановленный становится неначатым:
+его запускают заново кнопкой Start.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repo, err := getRepo()
+		if err != nil {
+			return err
+		}
+		config, err := newIntervalConfig(repo)
+		if err != nil {
+			return err
+		}
+		return resetAction(os.Stdout, config)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resetCmd)
+}
+
+func resetAction(out io.Writer, config *pomodoro.IntevalConfig) error {
+	// Сбросить можно только уже созданный интервал - GetInterval создал бы
+	// новый только ради ошибки
+	i, err := config.Repository().Last()
+	if errors.Is(err, pomodoro.ErrNoIntervals) {
+		return pomodoro.ErrIntervalNotRunning
+	}
+	if err != nil {
+		return err
+	}
+	if err := i.Reset(config); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Интервал %s #%d начат заново: %s\n", i.Category, i.ID, i.PlannedDuration)
+	return nil
+}
@@ -24,11 +24,12 @@ package cmd
 import (
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
+	"vegorov.ru/go-cli/pomo/api"
 	"vegorov.ru/go-cli/pomo/app"
 	"vegorov.ru/go-cli/pomo/pomodoro"
 
@@ -42,6 +43,17 @@ var cfgFile string
 var rootCmd = &cobra.Command{
 	Use:   "pomo",
 	Short: "A brief description of your application",
+	Long: `Запускает TUI помодоро-таймера.
+
+Конфиг-файл перечитывается на ходу: профили и длительности интервалов
+действуют со следующего интервала. Тема, компактная раскладка, клавиши,
+напоминания о простое и действие при выходе читаются только при запуске -
+чтобы их применить, перезапустите pomo.`,
+	// Лог настраиваем для всех команд, но у TUI и фонового serve
+	// он по умолчанию в файле
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setupLogging(cmd == cmd.Root() || cmd == serveCmd)
+	},
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -50,33 +62,93 @@ var rootCmd = &cobra.Command{
 			return err
 		}
 
-		config := pomodoro.NewConfig(repo,
-			viper.GetDuration("pomo"),
-			viper.GetDuration("short"),
-			viper.GetDuration("long"),
-		)
-		return rootAction(os.Stdout, config)
+		config, err := newIntervalConfig(repo)
+		if err != nil {
+			return err
+		}
+		if config.Task, err = cmd.Flags().GetString("task"); err != nil {
+			return err
+		}
+		if config.Tags, err = cmd.Flags().GetStringSlice("tag"); err != nil {
+			return err
+		}
+		return rootAction(os.Stdout, cmd, config)
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	log.Println("rootCmd Execute()")
-	cobra.CheckErr(rootCmd.Execute())
+	err := rootCmd.Execute()
+	if logCloser != nil {
+		logCloser.Close()
+	}
+	cobra.CheckErr(err)
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.pomo.yaml)")
+	rootCmd.PersistentFlags().String("profile", "", "Профиль настроек из конфиг-файла (по умолчанию - default)")
+	viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+	rootCmd.PersistentFlags().String("db", "", "Файл с историей интервалов (по умолчанию $XDG_DATA_HOME/pomo/pomo.json)")
+	viper.BindPFlag("db", rootCmd.PersistentFlags().Lookup("db"))
+
+	rootCmd.PersistentFlags().String("log-level", "",
+		"Уровень лога: debug, info, warn, error (по умолчанию info для TUI, warn для команд)")
+	rootCmd.PersistentFlags().String("log-format", logFormatText, "Формат лога: text или json")
+	rootCmd.PersistentFlags().String("log-file", "",
+		"Файл лога или stderr (по умолчанию для TUI - $XDG_STATE_HOME/pomo/pomo.log, для команд - stderr)")
+	viper.BindPFlag("log.level", rootCmd.PersistentFlags().Lookup("log-level"))
+	viper.BindPFlag("log.format", rootCmd.PersistentFlags().Lookup("log-format"))
+	viper.BindPFlag("log.file", rootCmd.PersistentFlags().Lookup("log-file"))
+
+	addIntervalFlags(rootCmd)
+
+	// Задача и метки относятся только к запуску TUI - поэтому флаги не persistent
+	rootCmd.Flags().String("task", "", "Задача, над которой работаем")
+	rootCmd.Flags().StringSlice("tag", nil, "Метки интервалов (можно несколько)")
+
+	// HTTP API работает вместе с TUI; токен - только в конфиг-файле (api.token),
+	// чтобы не светить его в списке процессов
+	rootCmd.Flags().Bool("api", false, "Включить HTTP API на localhost")
+	rootCmd.Flags().Int("api-port", api.DefaultPort, "Порт HTTP API")
+	viper.BindPFlag("api.enabled", rootCmd.Flags().Lookup("api"))
+	viper.BindPFlag("api.port", rootCmd.Flags().Lookup("api-port"))
+
+	rootCmd.Flags().Duration("max-pause", 0, "Максимальная пауза, после неё интервал отменяется (0 - без ограничения)")
+	rootCmd.Flags().String("pause-action", pomodoro.PauseActionCancel,
+		"Что делать по истечении максимальной паузы: cancel или reset (начать заново)")
+	viper.BindPFlag("pause.max", rootCmd.Flags().Lookup("max-pause"))
+	viper.BindPFlag("pause.action", rootCmd.Flags().Lookup("pause-action"))
+
+	rootCmd.Flags().String("theme", app.DefaultTheme, "Цветовая тема: dark, light, high-contrast или своя из конфиг-файла")
+	viper.BindPFlag("theme", rootCmd.Flags().Lookup("theme"))
+	rootCmd.Flags().Bool("compact", false, "Компактная раскладка - строка с таймером, без кнопок")
+	viper.BindPFlag("compact", rootCmd.Flags().Lookup("compact"))
+	bindIntervalFlags(rootCmd)
+}
 
-	rootCmd.Flags().DurationP("pomo", "p", 25*time.Minute, "Продолжительность Pomodoro")
-	rootCmd.Flags().DurationP("short", "s", 5*time.Minute, "Продолжительность короткого перерыва")
-	rootCmd.Flags().DurationP("long", "l", 15*time.Minute, "Продолжительность длинного перерыва")
+// Флаги интервалов - они же ключи настроек
+var intervalFlags = []string{"pomo", "short", "long", "overtime", "mode"}
+
+// Флаги продолжительностей и режима. Нужны только там, где идут интервалы, -
+// корневой команде (TUI) и serve; остальным командам они ни к чему
+// и заняли бы короткие флаги вроде -p.
+func addIntervalFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationP("pomo", "p", 25*time.Minute, "Продолжительность Pomodoro")
+	cmd.Flags().DurationP("short", "s", 5*time.Minute, "Продолжительность короткого перерыва")
+	cmd.Flags().DurationP("long", "l", 15*time.Minute, "Продолжительность длинного перерыва")
+	cmd.Flags().BoolP("overtime", "o", false, "Режим переработки: интервал не завершается сам, жми Finish")
+	cmd.Flags().StringP("mode", "m", pomodoro.ModeClassic, "Режим работы: classic или flowtime")
+}
 
-	viper.BindPFlag("pomo", rootCmd.Flags().Lookup("pomo"))
-	viper.BindPFlag("short", rootCmd.Flags().Lookup("short"))
-	viper.BindPFlag("long", rootCmd.Flags().Lookup("long"))
+// Привязывает настройки к флагам интервалов команды. Ключ привязан к одному
+// флагу, поэтому serve перепривязывает их к своим флагам при запуске.
+func bindIntervalFlags(cmd *cobra.Command) {
+	for _, name := range intervalFlags {
+		viper.BindPFlag(name, cmd.Flags().Lookup(name))
+	}
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -102,11 +174,33 @@ func initConfig() {
 	}
 }
 
-func rootAction(out io.Writer, config *pomodoro.IntevalConfig) error {
-	log.Println("rootAction")
-	a, err := app.New(config)
+func rootAction(out io.Writer, cmd *cobra.Command, config *pomodoro.IntevalConfig) error {
+	slog.Info("Запуск TUI", "profile", config.ActiveProfile(), "config", viper.ConfigFileUsed())
+	ln, err := listenAPI()
 	if err != nil {
 		return err
 	}
+	// Настройки вебхуков проверяем до запуска TUI - как и порт API
+	if _, err := readWebhooks(); err != nil {
+		return err
+	}
+
+	opts, err := tuiOptions()
+	if err != nil {
+		return err
+	}
+	if sharedRepo {
+		opts.Quit.Background = func() error { return spawnServe(cmd, config) }
+	}
+	a, err := app.New(config, opts)
+	if err != nil {
+		return err
+	}
+	watchConfig(config, opts, a.Notify)
+	serveAPI(a.Context(), a.Notify, config, ln)
+	if err := startWebhooks(a.Context(), a.Notify, config); err != nil {
+		slog.Error("Вебхуки отключены", "error", err)
+		a.Notify("Вебхуки отключены: " + err.Error())
+	}
 	return a.Run()
 }
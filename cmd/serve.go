@@ -0,0 +1,205 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"vegorov.ru/go-cli/pomo/api"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Фоновый режим без TUI: интервал, вебхуки и HTTP API",
+	Long: `Работает без экрана: доводит до конца исполняющийся интервал,
+отправляет вебхуки и обслуживает HTTP API, если он включён.
+
+TUI запускает serve --until-done сам, когда при выходе выбирают
+"оставить в фоне", - тогда serve завершится вместе с интервалом
+(или когда его поставят на паузу через API или pomo в другом терминале).
+Без --until-done serve работает, пока его не остановят; по SIGINT
+и SIGTERM исполняющийся интервал отменяется. Лог - как у TUI, в файле.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		untilDone, err := cmd.Flags().GetBool("until-done")
+		if err != nil {
+			return err
+		}
+		// Флаги API у корневой команды свои и к serve не относятся -
+		// поэтому свои флаги serve применяем поверх настроек вручную
+		if cmd.Flags().Changed("api") {
+			enabled, _ := cmd.Flags().GetBool("api")
+			viper.Set("api.enabled", enabled)
+		}
+		if cmd.Flags().Changed("api-port") {
+			port, _ := cmd.Flags().GetInt("api-port")
+			viper.Set("api.port", port)
+		}
+		if cmd.Flags().Changed("max-pause") {
+			limit, _ := cmd.Flags().GetDuration("max-pause")
+			viper.Set("pause.max", limit)
+		}
+		if cmd.Flags().Changed("pause-action") {
+			action, _ := cmd.Flags().GetString("pause-action")
+			viper.Set("pause.action", action)
+		}
+		bindIntervalFlags(cmd)
+
+		repo, err := getRepo()
+		if err != nil {
+			return err
+		}
+		config, err := newIntervalConfig(repo)
+		if err != nil {
+			return err
+		}
+
+		// Терминал, из которого запускали, могут закрыть - это не повод выходить
+		signal.Ignore(syscall.SIGHUP)
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return serveAction(ctx, config, untilDone)
+	},
+}
+
+func init() {
+	serveCmd.Flags().Bool("until-done", false, "Выйти, когда исполняющийся интервал завершится")
+	serveCmd.Flags().Bool("api", false, "Включить HTTP API на localhost")
+	serveCmd.Flags().Int("api-port", api.DefaultPort, "Порт HTTP API")
+	serveCmd.Flags().Duration("max-pause", 0, "Максимальная пауза, после неё интервал отменяется (0 - без ограничения)")
+	serveCmd.Flags().String("pause-action", pomodoro.PauseActionCancel,
+		"Что делать по истечении максимальной паузы: cancel или reset (начать заново)")
+	addIntervalFlags(serveCmd)
+	rootCmd.AddCommand(serveCmd)
+}
+
+func serveAction(ctx context.Context, config *pomodoro.IntevalConfig, untilDone bool) error {
+	slog.Info("Фоновый режим", "until_done", untilDone, "config", viper.ConfigFileUsed())
+
+	i, err := config.Repository().Last()
+	if err != nil && !errors.Is(err, pomodoro.ErrNoIntervals) {
+		return err
+	}
+	running := err == nil && i.State == pomodoro.StateRunning
+	if untilDone && !running {
+		slog.Info("Исполняющегося интервала нет - фоновый режим не нужен")
+		return nil
+	}
+
+	ln, err := listenAPIRetry()
+	if err != nil {
+		return err
+	}
+	// Экрана нет - ошибки API и вебхуков остаются только в логе
+	notify := func(string) {}
+	serveAPI(ctx, notify, config, ln)
+	if err := startWebhooks(ctx, notify, config); err != nil {
+		return err
+	}
+
+	if running {
+		slog.Info("Интервал подхвачен", "id", i.ID, "category", i.Category)
+		// Follow возвращается и по сигналу - уже записав отмену интервала,
+		// поэтому ждём его, а не контекст
+		noop := func(pomodoro.Interval) {}
+		if err := i.Follow(ctx, config, noop, noop, noop); err != nil || untilDone {
+			return err
+		}
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// Занимает порт API. TUI, запустивший serve при выходе, может ещё
+// держать порт - поэтому несколько раз пробуем снова.
+func listenAPIRetry() (net.Listener, error) {
+	var err error
+	for range 10 {
+		var ln net.Listener
+		if ln, err = listenAPI(); err == nil {
+			return ln, nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return nil, err
+}
+
+// Запускает pomo serve --until-done отдельным процессом - он доведёт
+// исполняющийся интервал до конца после выхода из TUI.
+// Настройки из флагов TUI (команды cmd) передаём флагами, остальное serve
+// прочитает сам. Профиль - действующий в config: его могли переключить в TUI.
+func spawnServe(cmd *cobra.Command, config *pomodoro.IntevalConfig) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"serve", "--until-done"}
+	if path := viper.ConfigFileUsed(); path != "" {
+		args = append(args, "--config", path)
+	}
+	if path := viper.GetString("db"); path != "" {
+		args = append(args, "--db", path)
+	}
+	args = append(args, "--profile", config.ActiveProfile())
+	// Флаги интервалов задают профиль по умолчанию, а от режима переработки
+	// зависит, завершится ли исполняющийся интервал сам. Предел паузы -
+	// чтобы паузы через API serve ограничивал так же, как TUI.
+	for _, name := range slices.Concat(intervalFlags, []string{"max-pause", "pause-action"}) {
+		if f := cmd.Flags().Lookup(name); f.Changed {
+			args = append(args, fmt.Sprintf("--%s=%s", name, f.Value))
+		}
+	}
+	if viper.GetBool("api.enabled") {
+		args = append(args, "--api", "--api-port", strconv.Itoa(viper.GetInt("api.port")))
+	}
+	if level := viper.GetString("log.level"); level != "" {
+		args = append(args, "--log-level", level)
+	}
+	// stderr у фонового процесса нет - тогда лог в файл по умолчанию
+	if file := viper.GetString("log.file"); file != "" && file != logStderr {
+		args = append(args, "--log-file", file)
+	}
+	args = append(args, "--log-format", viper.GetString("log.format"))
+
+	serve := exec.Command(exe, args...)
+	if err := serve.Start(); err != nil {
+		return fmt.Errorf("pomo serve: %w", err)
+	}
+	slog.Info("Запущен фоновый режим", "pid", serve.Process.Pid, "args", args)
+	return serve.Process.Release()
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/spf13/viper"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Строка таблицы коэффициентов Flowtime в конфиг-файле
+type flowtimeRatio struct {
+	UpTo  time.Duration `mapstructure:"up_to"`
+	Ratio float64       `mapstructure:"ratio"`
+}
+
+// Тип интервала в конфиг-файле
+type intervalType struct {
+	Name     string        `mapstructure:"name"`
+	Duration time.Duration `mapstructure:"duration"`
+	Break    bool          `mapstructure:"break"`
+}
+
+// Собирает конфигурацию интервалов из флагов, переменных окружения и конфиг-файла
+// и делает действующим профиль из --profile (или из ключа profile конфиг-файла)
+func newIntervalConfig(repo pomodoro.Repository) (*pomodoro.IntevalConfig, error) {
+	profiles, err := readProfiles()
+	if err != nil {
+		return nil, err
+	}
+	if err := pomodoro.ValidateProfiles(profiles); err != nil {
+		return nil, err
+	}
+
+	config := pomodoro.NewConfig(repo,
+		profiles[0].PomodoroDuration,
+		profiles[0].ShortBreakDuration,
+		profiles[0].LongBreakDuration,
+	)
+	config.SetProfiles(profiles)
+	if err := config.UseProfile(activeProfile()); err != nil {
+		return nil, err
+	}
+
+	if config.MaxPause, config.PauseAction, err = readPauseLimit(); err != nil {
+		return nil, err
+	}
+
+	// Интервал мог остаться на паузе дольше MaxPause с прошлого раза -
+	// команды и serve должны видеть его уже отменённым или начатым заново
+	i, expired, err := pomodoro.ExpirePause(config)
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		slog.Info("Пауза истекла, пока её никто не проверял",
+			"id", i.ID, "max_pause", config.MaxPause, "action", config.PauseAction)
+	}
+	return config, nil
+}
+
+// Ограничение паузы: pause.max и pause.action - одни на все профили
+func readPauseLimit() (time.Duration, string, error) {
+	maxPause := viper.GetDuration("pause.max")
+	action := viper.GetString("pause.action")
+	if err := pomodoro.ValidatePauseLimit(maxPause, action); err != nil {
+		return 0, "", err
+	}
+	return maxPause, action, nil
+}
+
+// Имя профиля, выбранного флагом или в конфиг-файле
+func activeProfile() string {
+	if name := viper.GetString("profile"); name != "" {
+		return name
+	}
+	return pomodoro.DefaultProfile
+}
+
+// Читает профили: первым идёт профиль по умолчанию из настроек верхнего уровня,
+// за ним - именованные профили из списка profiles конфиг-файла.
+// Незаданные в именованном профиле значения берутся из профиля по умолчанию.
+//
+// profiles:
+//   - name: coding
+//     pomo: 50m
+//     short: 10m
+//   - name: meetings
+//     mode: flowtime
+func readProfiles() ([]pomodoro.Profile, error) {
+	// Значения по умолчанию берём из NewConfig - чтобы не дублировать их здесь
+	defaults := pomodoro.NewConfig(nil, 0, 0, 0)
+	base := pomodoro.Profile{
+		Name:               pomodoro.DefaultProfile,
+		PomodoroDuration:   defaults.PomodoroDuration,
+		ShortBreakDuration: defaults.ShortBreakDuration,
+		LongBreakDuration:  defaults.LongBreakDuration,
+		Mode:               defaults.Mode,
+		FlowtimeRatios:     defaults.FlowtimeRatios,
+	}
+
+	base, err := readProfile(viper.GetViper(), base)
+	if err != nil {
+		return nil, err
+	}
+	profiles := []pomodoro.Profile{base}
+
+	var items []map[string]any
+	if err := viper.UnmarshalKey("profiles", &items); err != nil {
+		return nil, fmt.Errorf("%w: %v", pomodoro.ErrInvalidProfile, err)
+	}
+
+	for _, item := range items {
+		v := viper.New()
+		if err := v.MergeConfigMap(item); err != nil {
+			return nil, err
+		}
+
+		p := base
+		// Собственное расписание профиль по умолчанию не передаёт -
+		// иначе его нельзя было бы отменить в именованном профиле
+		p.Schedule = pomodoro.Schedule{}
+		p.Name = v.GetString("name")
+		if p.Name == "" || p.Name == pomodoro.DefaultProfile {
+			return nil, fmt.Errorf("%w: у профиля должно быть имя, отличное от %q",
+				pomodoro.ErrInvalidProfile, pomodoro.DefaultProfile)
+		}
+		if slices.ContainsFunc(profiles, func(e pomodoro.Profile) bool { return e.Name == p.Name }) {
+			return nil, fmt.Errorf("%w: профиль %q описан дважды", pomodoro.ErrInvalidProfile, p.Name)
+		}
+
+		if p, err = readProfile(v, p); err != nil {
+			return nil, fmt.Errorf("профиль %q: %w", p.Name, err)
+		}
+		profiles = append(profiles, p)
+	}
+
+	return profiles, nil
+}
+
+// Читает настройки профиля из v; то, что в v не задано, остаётся из p.
+// Значения здесь только разбираются - проверяет их pomodoro.ValidateProfiles.
+func readProfile(v *viper.Viper, p pomodoro.Profile) (pomodoro.Profile, error) {
+	if v.IsSet("pomo") {
+		p.PomodoroDuration = v.GetDuration("pomo")
+	}
+	if v.IsSet("short") {
+		p.ShortBreakDuration = v.GetDuration("short")
+	}
+	if v.IsSet("long") {
+		p.LongBreakDuration = v.GetDuration("long")
+	}
+	if v.IsSet("overtime") {
+		p.Overtime = v.GetBool("overtime")
+	}
+
+	if v.IsSet("mode") {
+		p.Mode = v.GetString("mode")
+	}
+
+	// Таблица коэффициентов Flowtime задаётся только в конфиг-файле:
+	// flowtime:
+	//   - up_to: 25m
+	//     ratio: 0.2
+	//   - ratio: 0.17
+	if v.IsSet("flowtime") {
+		var rows []flowtimeRatio
+		if err := v.UnmarshalKey("flowtime", &rows); err != nil {
+			return p, fmt.Errorf("%w: %v", pomodoro.ErrInvalidRatios, err)
+		}
+		ratios := make([]pomodoro.FlowtimeRatio, 0, len(rows))
+		for _, r := range rows {
+			ratios = append(ratios, pomodoro.FlowtimeRatio{UpTo: r.UpTo, Ratio: r.Ratio})
+		}
+		p.FlowtimeRatios = ratios
+	}
+
+	// Собственное расписание задаётся только в конфиг-файле.
+	// Типы - списком, а не словарём: viper приводит ключи словарей к нижнему регистру.
+	// types:
+	//   - name: DeepWork
+	//     duration: 50m
+	//   - name: Rest
+	//     duration: 10m
+	//     break: true
+	// sequence: [DeepWork, Rest, DeepWork, Rest]
+	// Типы без последовательности не применились бы никуда - это ошибка, а не молчание
+	if v.IsSet("types") && !v.IsSet("sequence") {
+		return p, fmt.Errorf("%w: types задан без sequence - типы используются только в последовательности",
+			pomodoro.ErrInvalidConfig)
+	}
+	if v.IsSet("sequence") {
+		var types []intervalType
+		if err := v.UnmarshalKey("types", &types); err != nil {
+			return p, fmt.Errorf("%w: %v", pomodoro.ErrInvalidSchedule, err)
+		}
+		// Классические типы доступны в последовательности без описания,
+		// но их можно переопределить
+		s := pomodoro.ClassicSchedule(p.PomodoroDuration, p.ShortBreakDuration, p.LongBreakDuration)
+		s.Sequence = v.GetStringSlice("sequence")
+		for _, t := range types {
+			it := pomodoro.IntervalType{Category: t.Name, Duration: t.Duration, Break: t.Break}
+			if k := slices.IndexFunc(s.Types, func(c pomodoro.IntervalType) bool {
+				return c.Category == t.Name
+			}); k >= 0 {
+				s.Types[k] = it
+				continue
+			}
+			s.Types = append(s.Types, it)
+		}
+		p.Schedule = s
+	}
+
+	return p, nil
+}
@@ -0,0 +1,270 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// taskCmd represents the task command
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Список задач с оценкой в помидорах",
+	Long: `Задачи, к которым привязываются рабочие интервалы. В TUI задачу
+для следующих интервалов выбирают из списка; у каждой задачи видно,
+сколько помидоров на неё ушло и сколько было оценено.`,
+}
+
+var taskAddCmd = &cobra.Command{
+	Use:   "add TITLE...",
+	Short: "Добавить задачу",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+		estimate, err := cmd.Flags().GetInt("estimate")
+		if err != nil {
+			return err
+		}
+
+		config, err := taskConfig()
+		if err != nil {
+			return err
+		}
+		return taskAddAction(os.Stdout, config, pomodoro.Task{
+			Title:    strings.Join(args, " "),
+			Project:  project,
+			Estimate: estimate,
+		})
+	},
+}
+
+var taskListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Список задач: оценка и факт в помидорах",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return err
+		}
+		config, err := taskConfig()
+		if err != nil {
+			return err
+		}
+		return taskListAction(os.Stdout, config, all)
+	},
+}
+
+var taskDoneCmd = &cobra.Command{
+	Use:   "done ID",
+	Short: "Отметить задачу выполненной",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %q", pomodoro.ErrUnknownTask, args[0])
+		}
+		config, err := taskConfig()
+		if err != nil {
+			return err
+		}
+		return taskDoneAction(os.Stdout, config, id)
+	},
+}
+
+var taskReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Оценка против факта: по задачам, проектам и по неделям",
+	Long: `Сравнивает оценку задач в помидорах с фактом - завершёнными рабочими
+интервалами задачи. Сводки по проектам и тренд - только по выполненным
+задачам: факт открытых ещё растёт. Задачи без оценки в отчёт не попадают.
+
+ФАКТ/ОЦЕНКА больше 1 - задачи недооцениваются, меньше 1 - переоцениваются.
+ТОЧНОСТЬ - в среднем по задачам меньшее из оценки и факта к большему.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		weeks, err := cmd.Flags().GetInt("weeks")
+		if err != nil {
+			return err
+		}
+		if weeks < 1 {
+			return fmt.Errorf("--weeks %d: нужна хотя бы одна неделя", weeks)
+		}
+		config, err := taskConfig()
+		if err != nil {
+			return err
+		}
+		return taskReportAction(os.Stdout, config, weeks)
+	},
+}
+
+func init() {
+	// Без коротких флагов: -p у pomo - продолжительность Pomodoro
+	taskAddCmd.Flags().String("project", "", "Проект задачи")
+	taskAddCmd.Flags().Int("estimate", 0, "Оценка в помидорах (0 - без оценки)")
+	taskListCmd.Flags().Bool("all", false, "Вместе с выполненными задачами")
+	taskReportCmd.Flags().Int("weeks", 8, "За сколько недель показать тренд точности")
+
+	taskCmd.AddCommand(taskAddCmd)
+	taskCmd.AddCommand(taskListCmd)
+	taskCmd.AddCommand(taskDoneCmd)
+	taskCmd.AddCommand(taskReportCmd)
+	rootCmd.AddCommand(taskCmd)
+}
+
+func taskConfig() (*pomodoro.IntevalConfig, error) {
+	repo, err := getRepo()
+	if err != nil {
+		return nil, err
+	}
+	return newIntervalConfig(repo)
+}
+
+func taskAddAction(out io.Writer, config *pomodoro.IntevalConfig, t pomodoro.Task) error {
+	t, err := pomodoro.AddTask(config, t)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Задача #%d добавлена: %s\n", t.ID, t.Title)
+	return nil
+}
+
+func taskListAction(out io.Writer, config *pomodoro.IntevalConfig, all bool) error {
+	list, err := pomodoro.TaskList(config, all)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Fprintln(out, "Задач нет - добавь: pomo task add НАЗВАНИЕ")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tЗАДАЧА\tПРОЕКТ\tФАКТ/ОЦЕНКА\tСТАТУС")
+	for _, p := range list {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", p.ID, p.Title, projectName(p.Project), p.Progress(), taskStatus(p))
+	}
+	return tw.Flush()
+}
+
+func taskStatus(p pomodoro.TaskProgress) string {
+	switch {
+	case p.Done:
+		return "выполнена"
+	case p.OverEstimate():
+		return "сверх оценки"
+	default:
+		return "в работе"
+	}
+}
+
+func taskDoneAction(out io.Writer, config *pomodoro.IntevalConfig, id int64) error {
+	t, err := pomodoro.CompleteTask(config, id)
+	if err != nil {
+		return err
+	}
+	list, err := pomodoro.TaskList(config, true)
+	if err != nil {
+		return err
+	}
+	for _, p := range list {
+		if p.ID == t.ID {
+			fmt.Fprintf(out, "Задача #%d выполнена: %s, помидоров %s\n", t.ID, t.Title, p.Progress())
+		}
+	}
+	return nil
+}
+
+func taskReportAction(out io.Writer, config *pomodoro.IntevalConfig, weeks int) error {
+	r, err := pomodoro.Estimates(config, weeks)
+	if err != nil {
+		return err
+	}
+	if len(r.Tasks) == 0 {
+		fmt.Fprintln(out, "Задач с оценкой нет - добавь: pomo task add НАЗВАНИЕ --estimate N")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tЗАДАЧА\tПРОЕКТ\tОЦЕНКА\tФАКТ\tСТАТУС")
+	for _, p := range r.Tasks {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%d\t%s\n",
+			p.ID, p.Title, projectName(p.Project), p.Estimate, p.Actual, taskStatus(p))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if r.Total.Tasks == 0 {
+		fmt.Fprintln(out, "\nВыполненных задач с оценкой пока нет - сводки будут после pomo task done")
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(tw, "ПРОЕКТ\tЗАДАЧ\tОЦЕНКА\tФАКТ\tФАКТ/ОЦЕНКА\tТОЧНОСТЬ")
+	row := func(name string, s pomodoro.EstimateStats) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.2f\t%.0f%%\n",
+			name, s.Tasks, s.Estimated, s.Actual, s.Ratio(), 100*s.Accuracy())
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Projects)) {
+		row(projectName(name), r.Projects[name])
+	}
+	row("Всего", r.Total)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(tw, "НЕДЕЛЯ\tЗАДАЧ\tФАКТ/ОЦЕНКА\tТОЧНОСТЬ\t")
+	for _, w := range r.Trend {
+		if w.Tasks == 0 {
+			fmt.Fprintf(tw, "%s\t0\t-\t-\t\n", w.Start.Format(time.DateOnly))
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.2f\t%.0f%%\t%s\n", w.Start.Format(time.DateOnly), w.Tasks,
+			w.Ratio(), 100*w.Accuracy(), strings.Repeat("█", int(math.Round(10*w.Accuracy()))))
+	}
+	return tw.Flush()
+}
+
+// Проект для таблиц: "-", если не задан
+func projectName(project string) string {
+	if project == "" {
+		return "-"
+	}
+	return project
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/viper"
+	"vegorov.ru/go-cli/pomo/app"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func init() {
+	// Напоминание о простое по умолчанию - звонком терминала
+	viper.SetDefault("idle.bell", true)
+	// При выходе с идущим интервалом по умолчанию предлагаем паузу -
+	// так ничего не теряется
+	viper.SetDefault("quit.action", app.QuitPause)
+}
+
+// Собственная тема из конфиг-файла: встроенная тема base с заменой части цветов.
+// Категории - списком, а не словарём: viper приводит ключи словарей
+// к нижнему регистру, а имена категорий к регистру чувствительны.
+//
+//	themes:
+//	  - name: solarized
+//	    base: light
+//	    colors:
+//	      border: "#93a1a1"
+//	      work: 160
+//	    categories:
+//	      - name: DeepWork
+//	        color: navy
+type themeConfig struct {
+	Name       string            `mapstructure:"name"`
+	Base       string            `mapstructure:"base"`
+	Colors     map[string]string `mapstructure:"colors"`
+	Categories []struct {
+		Name  string `mapstructure:"name"`
+		Color string `mapstructure:"color"`
+	} `mapstructure:"categories"`
+}
+
+// Тема, выбранная ключом theme: встроенная или собственная из themes
+func readTheme() (app.Theme, error) {
+	name := viper.GetString("theme")
+
+	var themes []themeConfig
+	if err := viper.UnmarshalKey("themes", &themes); err != nil {
+		return app.Theme{}, fmt.Errorf("%w: themes: %v", pomodoro.ErrInvalidConfig, err)
+	}
+
+	// Собственные темы проверяем все, а не только выбранную -
+	// чтобы config validate находил ошибки в любой из них
+	var chosen *app.Theme
+	for _, tc := range themes {
+		t, err := tc.theme()
+		if err != nil {
+			return app.Theme{}, fmt.Errorf("%w: тема %q: %v", pomodoro.ErrInvalidConfig, tc.Name, err)
+		}
+		if t.Name == name {
+			chosen = &t
+		}
+	}
+	if chosen != nil {
+		return *chosen, nil
+	}
+	return app.BuiltinTheme(name)
+}
+
+func (tc themeConfig) theme() (app.Theme, error) {
+	if tc.Name == "" {
+		return app.Theme{}, errors.New("у темы должно быть имя")
+	}
+	base := tc.Base
+	if base == "" {
+		base = app.DefaultTheme
+	}
+	t, err := app.BuiltinTheme(base)
+	if err != nil {
+		return t, err
+	}
+	t.Name = tc.Name
+
+	for key, value := range tc.Colors {
+		if err := t.SetColor(key, value); err != nil {
+			return t, err
+		}
+	}
+	for _, c := range tc.Categories {
+		if err := t.SetCategory(c.Name, c.Color); err != nil {
+			return t, err
+		}
+	}
+	return t, nil
+}
+
+// Настройки TUI из конфига
+func tuiOptions() (app.Options, error) {
+	opts := app.Options{
+		Compact: viper.GetBool("compact"),
+		Idle: app.IdleOptions{
+			Nudge: viper.GetDuration("idle.nudge"),
+			Bell:  viper.GetBool("idle.bell"),
+			Hook:  viper.GetString("idle.hook"),
+			Pause: viper.GetDuration("idle.pause"),
+		},
+	}
+	if opts.Idle.Nudge < 0 {
+		return opts, fmt.Errorf("%w: idle.nudge %s меньше нуля", pomodoro.ErrInvalidConfig, opts.Idle.Nudge)
+	}
+	if opts.Idle.Pause < 0 {
+		return opts, fmt.Errorf("%w: idle.pause %s меньше нуля", pomodoro.ErrInvalidConfig, opts.Idle.Pause)
+	}
+
+	// Клавиши из конфига заменяют клавиши действий по умолчанию целиком
+	opts.Keys = app.DefaultKeymap().With(viper.GetStringMapStringSlice("keys"))
+	if err := opts.Keys.Validate(); err != nil {
+		return opts, fmt.Errorf("%w: keys: %v", pomodoro.ErrInvalidConfig, err)
+	}
+
+	opts.Quit.Default = viper.GetString("quit.action")
+	if err := app.ValidateQuitAction(opts.Quit.Default); err != nil {
+		return opts, fmt.Errorf("%w: quit.action: %v", pomodoro.ErrInvalidConfig, err)
+	}
+	var err error
+	opts.Theme, err = readTheme()
+	return opts, err
+}
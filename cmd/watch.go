@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"vegorov.ru/go-cli/pomo/app"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Следит за конфиг-файлом и применяет изменения на ходу.
+// Новые настройки действуют со следующего интервала - исполняющийся
+// дорабатывает по старым. Ошибки не роняют приложение, а уходят в notify.
+// Настройки TUI (tui - с которыми он запущен) на ходу не применяются:
+// виджеты получают цвета и клавиши при создании. Их только проверяем
+// и сообщаем, что нужен перезапуск.
+func watchConfig(config *pomodoro.IntevalConfig, tui app.Options, notify func(string)) {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		// Конфиг-файла нет - следить не за чем
+		return
+	}
+
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		opts, err := reloadConfig(config, path)
+		if err != nil {
+			slog.Warn("Конфиг не применён", "path", path, "error", err)
+		} else {
+			slog.Info("Конфиг перечитан", "path", path)
+		}
+		switch {
+		case errors.Is(err, pomodoro.ErrUnknownProfile):
+			notify(fmt.Sprintf("Профиль удалён из конфига - действует %s", config.ActiveProfile()))
+			return
+		case err != nil:
+			notify(fmt.Sprintf("Конфиг не применён: %v", err))
+			return
+		}
+		if !sameTUIOptions(opts, tui) {
+			notify("Конфиг перечитан - тема, клавиши и другие настройки TUI применятся после перезапуска pomo")
+			return
+		}
+		notify("Конфиг перечитан - изменения со следующего интервала")
+	})
+	viper.WatchConfig()
+}
+
+// Перечитывает настройки и применяет их к config.
+// Возвращает перечитанные настройки TUI - они уже проверены.
+func reloadConfig(config *pomodoro.IntevalConfig, path string) (app.Options, error) {
+	if err := checkConfigFile(path); err != nil {
+		return app.Options{}, err
+	}
+
+	// Неверная тема или клавиши - конфиг целиком не применяем,
+	// как не запустился бы с ним и pomo
+	opts, err := tuiOptions()
+	if err != nil {
+		return opts, err
+	}
+	profiles, err := readProfiles()
+	if err != nil {
+		return opts, err
+	}
+	if err := pomodoro.ValidateProfiles(profiles); err != nil {
+		return opts, err
+	}
+	return opts, pomodoro.ReloadProfiles(config, profiles)
+}
+
+// Одинаковы ли настройки TUI из конфига. Фоновый выход задаётся
+// не конфигом, а способом запуска, - его не сравниваем.
+func sameTUIOptions(a, b app.Options) bool {
+	a.Quit.Background, b.Quit.Background = nil, nil
+	return reflect.DeepEqual(a, b)
+}
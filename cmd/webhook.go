@@ -0,0 +1,93 @@
+/*
+Copyright © 2025 Vladimir Egorov
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/viper"
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/webhook"
+)
+
+// Адрес вебхука в конфиг-файле
+//
+// webhooks:
+//   - url: https://chat.example/hooks/pomo
+//     events: [start, done]
+//     secret: s3cret
+type webhookEndpoint struct {
+	URL    string   `mapstructure:"url"`
+	Events []string `mapstructure:"events"`
+	Secret string   `mapstructure:"secret"`
+}
+
+// Читает и проверяет адреса вебхуков
+func readWebhooks() ([]webhook.Endpoint, error) {
+	var items []webhookEndpoint
+	if err := viper.UnmarshalKey("webhooks", &items); err != nil {
+		return nil, fmt.Errorf("%w: webhooks: %v", pomodoro.ErrInvalidConfig, err)
+	}
+
+	endpoints := make([]webhook.Endpoint, 0, len(items))
+	for _, item := range items {
+		e := webhook.Endpoint{URL: item.URL, Events: item.Events, Secret: item.Secret}
+		if err := e.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %w", pomodoro.ErrInvalidConfig, err)
+		}
+		endpoints = append(endpoints, e)
+	}
+	return endpoints, nil
+}
+
+// Запускает отправку вебхуков на время работы TUI или фонового serve - пока
+// не завершится ctx. Очередь хранится в $XDG_STATE_HOME/pomo/webhooks.json -
+// неотправленное уйдёт при следующем запуске.
+func startWebhooks(ctx context.Context, notify func(string), config *pomodoro.IntevalConfig) error {
+	endpoints, err := readWebhooks()
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+
+	queue, err := xdgPath("XDG_STATE_HOME", ".local/state", "webhooks.json")
+	if err != nil {
+		return err
+	}
+
+	d, err := webhook.New(webhook.Options{
+		Endpoints: endpoints,
+		QueueFile: queue,
+		OnError: func(err error) {
+			slog.Error("Вебхуки", "error", err)
+			notify("Вебхуки: " + err.Error())
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	config.Subscribe(d.Publish)
+	go d.Run(ctx)
+	return nil
+}
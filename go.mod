@@ -3,6 +3,7 @@ module vegorov.ru/go-cli/pomo
 go 1.24.0
 
 require (
+	github.com/fsnotify/fsnotify v1.8.0
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/mum4k/termdash v0.20.0
 	github.com/spf13/cobra v1.9.1
@@ -10,7 +11,6 @@ require (
 )
 
 require (
-	github.com/fsnotify/fsnotify v1.8.0 // indirect
 	github.com/gdamore/encoding v1.0.0 // indirect
 	github.com/gdamore/tcell/v2 v2.7.4 // indirect
 	github.com/go-viper/mapstructure/v2 v2.2.1 // indirect
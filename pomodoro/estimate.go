@@ -0,0 +1,107 @@
+package pomodoro
+
+import "time"
+
+// Оценка против факта по группе выполненных задач с оценкой
+type EstimateStats struct {
+	Tasks     int
+	Estimated int
+	Actual    int
+	// Сумма точностей задач - для средней точности
+	accuracy float64
+}
+
+// Добавляет задачу в сводку
+func (s EstimateStats) add(p TaskProgress) EstimateStats {
+	s.Tasks++
+	s.Estimated += p.Estimate
+	s.Actual += p.Actual
+	s.accuracy += float64(min(p.Actual, p.Estimate)) / float64(max(p.Actual, p.Estimate))
+	return s
+}
+
+// Факт к оценке: больше 1 - задачи недооценивают, меньше 1 - переоценивают.
+// 0 - задач нет.
+func (s EstimateStats) Ratio() float64 {
+	if s.Estimated == 0 {
+		return 0
+	}
+	return float64(s.Actual) / float64(s.Estimated)
+}
+
+// Средняя точность оценки задачи от 0 до 1: у каждой задачи это меньшее
+// из оценки и факта, делённое на большее. 1 - все оценки точны.
+func (s EstimateStats) Accuracy() float64 {
+	if s.Tasks == 0 {
+		return 0
+	}
+	return s.accuracy / float64(s.Tasks)
+}
+
+// Точность оценок задач, выполненных за неделю
+type EstimateWeek struct {
+	// Понедельник недели
+	Start time.Time
+	EstimateStats
+}
+
+// Отчёт: оценка против факта
+type EstimateReport struct {
+	// Все задачи с оценкой - открытые и выполненные
+	Tasks []TaskProgress
+	// Выполненные задачи по проектам и все вместе. Факт открытых задач
+	// ещё растёт - в сводки они не идут.
+	Projects map[string]EstimateStats
+	Total    EstimateStats
+	// Точность по неделям выполнения задач, от старых недель к новым
+	Trend []EstimateWeek
+}
+
+// Формирует отчёт по задачам с фактом. Тренд - за weeks недель
+// по текущую (в которую попадает now) включительно.
+func NewEstimateReport(tasks []TaskProgress, weeks int, now time.Time) EstimateReport {
+	r := EstimateReport{Tasks: []TaskProgress{}, Projects: map[string]EstimateStats{}}
+
+	first := weekStart(now).AddDate(0, 0, -7*(weeks-1))
+	for k := range weeks {
+		r.Trend = append(r.Trend, EstimateWeek{Start: first.AddDate(0, 0, 7*k)})
+	}
+
+	for _, p := range tasks {
+		if p.Estimate == 0 {
+			continue
+		}
+		r.Tasks = append(r.Tasks, p)
+		if !p.Done {
+			continue
+		}
+		r.Projects[p.Project] = r.Projects[p.Project].add(p)
+		r.Total = r.Total.add(p)
+
+		if p.Completed.Before(first) {
+			continue
+		}
+		// Неделя - по дням от начала тренда: у недель с переходом
+		// на летнее время не ровно 7*24 часа
+		k := int(weekStart(p.Completed).Sub(first).Round(24*time.Hour).Hours()/24) / 7
+		if k < len(r.Trend) {
+			r.Trend[k].EstimateStats = r.Trend[k].add(p)
+		}
+	}
+	return r
+}
+
+// Отчёт по всем задачам с трендом за weeks недель
+func Estimates(config *IntevalConfig, weeks int) (EstimateReport, error) {
+	tasks, err := TaskList(config, true)
+	if err != nil {
+		return EstimateReport{}, err
+	}
+	return NewEstimateReport(tasks, weeks, time.Now()), nil
+}
+
+// Понедельник недели, в которую попадает t, по местному времени
+func weekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
@@ -0,0 +1,74 @@
+package pomodoro_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestEstimateReport(t *testing.T) {
+	// Среда; неделя тренда начинается с понедельника 13 октября
+	now := time.Date(2025, time.October, 15, 12, 0, 0, 0, time.Local)
+	done := func(id int64, project string, estimate, actual int, completed time.Time) pomodoro.TaskProgress {
+		return pomodoro.TaskProgress{
+			Task: pomodoro.Task{ID: id, Title: "Задача", Project: project, Estimate: estimate,
+				Done: true, Completed: completed},
+			Actual: actual,
+		}
+	}
+
+	tasks := []pomodoro.TaskProgress{
+		done(1, "work", 4, 2, now.AddDate(0, 0, -14)),
+		done(2, "work", 2, 4, now.AddDate(0, 0, -7)),
+		done(3, "home", 2, 2, now),
+		// Без оценки - в отчёт не попадает
+		done(4, "home", 0, 3, now),
+		// Выполнена давно - в сводках есть, в тренде нет
+		done(5, "home", 1, 1, now.AddDate(0, -3, 0)),
+		// Открытая - только в списке задач
+		{Task: pomodoro.Task{ID: 6, Title: "Открытая", Estimate: 3}, Actual: 5},
+	}
+
+	r := pomodoro.NewEstimateReport(tasks, 3, now)
+
+	if len(r.Tasks) != 5 {
+		t.Errorf("Ожидали задач с оценкой: 5, а получили: %d", len(r.Tasks))
+	}
+
+	testCases := []struct {
+		name        string
+		stats       pomodoro.EstimateStats
+		expTasks    int
+		expRatio    float64
+		expAccuracy float64
+	}{
+		{"Total", r.Total, 4, 9.0 / 9.0, (0.5 + 0.5 + 1 + 1) / 4},
+		{"Work", r.Projects["work"], 2, 6.0 / 6.0, 0.5},
+		{"Home", r.Projects["home"], 2, 3.0 / 3.0, 1},
+		{"Week1", r.Trend[0].EstimateStats, 1, 0.5, 0.5},
+		{"Week2", r.Trend[1].EstimateStats, 1, 2, 0.5},
+		{"Week3", r.Trend[2].EstimateStats, 1, 1, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.stats.Tasks != tc.expTasks {
+				t.Errorf("Ожидали задач: %d, а получили: %d", tc.expTasks, tc.stats.Tasks)
+			}
+			if math.Abs(tc.stats.Ratio()-tc.expRatio) > 1e-9 {
+				t.Errorf("Ожидали факт к оценке: %.2f, а получили: %.2f", tc.expRatio, tc.stats.Ratio())
+			}
+			if math.Abs(tc.stats.Accuracy()-tc.expAccuracy) > 1e-9 {
+				t.Errorf("Ожидали точность: %.2f, а получили: %.2f", tc.expAccuracy, tc.stats.Accuracy())
+			}
+		})
+	}
+
+	monday := time.Date(2025, time.October, 13, 0, 0, 0, 0, time.Local)
+	if !r.Trend[2].Start.Equal(monday) || !r.Trend[0].Start.Equal(monday.AddDate(0, 0, -14)) {
+		t.Errorf("Ожидали недели с %v по %v, а получили: %v - %v",
+			monday.AddDate(0, 0, -14), monday, r.Trend[0].Start, r.Trend[2].Start)
+	}
+}
@@ -0,0 +1,95 @@
+package pomodoro
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Типы событий интервала
+const (
+	EventStart  = "start"
+	EventResume = "resume"
+	EventTick   = "tick"
+	EventPause  = "pause"
+	EventDone   = "done"
+	EventCancel = "cancel"
+	// Записано прерывание - состояние интервала не меняется
+	EventInterrupt = "interrupt"
+	// Интервал начат заново: отработанное время сброшено
+	EventReset = "reset"
+	// У интервала сменилась задача - состояние не меняется
+	EventTask = "task"
+)
+
+// Событие интервала - переход состояния или очередной тик.
+// Interval - состояние интервала сразу после события.
+type Event struct {
+	Type     string
+	Time     time.Time
+	Interval Interval
+}
+
+// Подписчик на события. Вызывается синхронно в той горутине, где
+// произошло событие (в том числе в tick), поэтому долго блокироваться
+// не должен - медленную обработку подписчик выносит к себе сам.
+type Subscriber func(Event)
+
+// Подписчики конфига. Указатель - по той же причине, что и мьютекс.
+type subscribers struct {
+	sync.RWMutex
+	next int
+	subs map[int]Subscriber
+}
+
+// Подписывает s на события интервалов этого конфига - кто бы их ни вызвал:
+// TUI, HTTP API или команда. Возвращает функцию отписки.
+func (c *IntevalConfig) Subscribe(s Subscriber) (unsubscribe func()) {
+	c.events.Lock()
+	defer c.events.Unlock()
+
+	id := c.events.next
+	c.events.next++
+	c.events.subs[id] = s
+
+	return func() {
+		c.events.Lock()
+		defer c.events.Unlock()
+		delete(c.events.subs, id)
+	}
+}
+
+// Пишет событие в лог и рассылает подписчикам
+func (c *IntevalConfig) emit(typ string, i Interval) {
+	// Тики - каждую секунду, поэтому только на уровне Debug
+	level := slog.LevelInfo
+	if typ == EventTick {
+		level = slog.LevelDebug
+	}
+	slog.Log(context.Background(), level, "Интервал: "+typ,
+		"id", i.ID,
+		"category", i.Category,
+		"state", StateName(i.State),
+		"actual", i.ActualDuration,
+		"planned", i.PlannedDuration,
+		"profile", i.Profile,
+	)
+
+	// Конфиг, собранный не через NewConfig, событий не рассылает
+	if c.events == nil {
+		return
+	}
+
+	c.events.RLock()
+	subs := make([]Subscriber, 0, len(c.events.subs))
+	for _, s := range c.events.subs {
+		subs = append(subs, s)
+	}
+	c.events.RUnlock()
+
+	e := Event{Type: typ, Time: time.Now(), Interval: i}
+	for _, s := range subs {
+		s(e)
+	}
+}
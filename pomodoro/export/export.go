@@ -0,0 +1,178 @@
+// Выгрузка истории интервалов в CSV, JSON, JSON Lines и iCalendar.
+//
+// Имена колонок и полей стабильны - на них завязаны чужие таблицы и скрипты,
+// поэтому менять их нельзя, можно только добавлять новые в конец.
+// Время - в RFC 3339, продолжительности - в целых секундах.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Ошибки
+var (
+	ErrUnknownFormat = errors.New("неизвестный формат выгрузки")
+)
+
+// Форматы выгрузки
+const (
+	FormatCSV       = "csv"
+	FormatJSON      = "json"
+	FormatJSONLines = "jsonl"
+	FormatICS       = "ics"
+)
+
+// Форматы в порядке перечисления в подсказках
+var Formats = []string{FormatCSV, FormatJSON, FormatJSONLines, FormatICS}
+
+// Разделитель меток в колонке tags CSV
+const tagSeparator = ";"
+
+// Колонки CSV - совпадают с именами полей JSON
+var Columns = []string{
+	"id",
+	"start_time",
+	"end_time",
+	"category",
+	"break",
+	"state",
+	"profile",
+	"task",
+	"tags",
+	"planned_seconds",
+	"actual_seconds",
+	"overtime_seconds",
+	"pauses",
+	"paused_seconds",
+	"wall_end_time",
+}
+
+// Интервал в выгрузке
+type Record struct {
+	ID              int64    `json:"id"`
+	StartTime       string   `json:"start_time"`
+	EndTime         string   `json:"end_time"`
+	Category        string   `json:"category"`
+	Break           bool     `json:"break"`
+	State           string   `json:"state"`
+	Profile         string   `json:"profile"`
+	Task            string   `json:"task"`
+	Tags            []string `json:"tags"`
+	PlannedSeconds  int64    `json:"planned_seconds"`
+	ActualSeconds   int64    `json:"actual_seconds"`
+	OvertimeSeconds int64    `json:"overtime_seconds"`
+	Pauses          int      `json:"pauses"`
+	PausedSeconds   int64    `json:"paused_seconds"`
+	// Окончание по часам - вместе с паузами
+	WallEndTime string `json:"wall_end_time"`
+}
+
+// Формирует запись выгрузки из интервала
+func NewRecord(i pomodoro.Interval) Record {
+	tags := i.Tags
+	if tags == nil {
+		// В JSON - пустой массив, а не null
+		tags = []string{}
+	}
+	return Record{
+		ID:              i.ID,
+		StartTime:       i.StartTime.Format(time.RFC3339),
+		EndTime:         i.StartTime.Add(i.ActualDuration).Format(time.RFC3339),
+		Category:        i.Category,
+		Break:           i.Break,
+		State:           pomodoro.StateName(i.State),
+		Profile:         i.Profile,
+		Task:            i.Task,
+		Tags:            tags,
+		PlannedSeconds:  int64(i.PlannedDuration / time.Second),
+		ActualSeconds:   int64(i.ActualDuration / time.Second),
+		OvertimeSeconds: int64(i.Overtime() / time.Second),
+		Pauses:          len(i.Pauses),
+		PausedSeconds:   int64(i.PausedTime() / time.Second),
+		WallEndTime:     i.StartTime.Add(i.WallClock()).Format(time.RFC3339),
+	}
+}
+
+// Значения записи в порядке Columns
+func (r Record) values() []string {
+	return []string{
+		strconv.FormatInt(r.ID, 10),
+		r.StartTime,
+		r.EndTime,
+		r.Category,
+		strconv.FormatBool(r.Break),
+		r.State,
+		r.Profile,
+		r.Task,
+		strings.Join(r.Tags, tagSeparator),
+		strconv.FormatInt(r.PlannedSeconds, 10),
+		strconv.FormatInt(r.ActualSeconds, 10),
+		strconv.FormatInt(r.OvertimeSeconds, 10),
+		strconv.Itoa(r.Pauses),
+		strconv.FormatInt(r.PausedSeconds, 10),
+		r.WallEndTime,
+	}
+}
+
+// Выгружает интервалы в формате format
+func Write(w io.Writer, format string, intervals []pomodoro.Interval) error {
+	switch format {
+	case FormatCSV:
+		return CSV(w, intervals)
+	case FormatJSON:
+		return JSON(w, intervals)
+	case FormatJSONLines:
+		return JSONLines(w, intervals)
+	case FormatICS:
+		return ICS(w, intervals, ICSOptions{})
+	default:
+		return fmt.Errorf("%w: %q, допустимо: %s", ErrUnknownFormat, format, strings.Join(Formats, ", "))
+	}
+}
+
+// Выгружает интервалы в CSV с заголовком
+func CSV(w io.Writer, intervals []pomodoro.Interval) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Columns); err != nil {
+		return err
+	}
+	for _, i := range intervals {
+		if err := cw.Write(NewRecord(i).values()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Выгружает интервалы JSON-массивом
+func JSON(w io.Writer, intervals []pomodoro.Interval) error {
+	records := make([]Record, 0, len(intervals))
+	for _, i := range intervals {
+		records = append(records, NewRecord(i))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
+
+// Выгружает интервалы в JSON Lines - по объекту на строку
+func JSONLines(w io.Writer, intervals []pomodoro.Interval) error {
+	enc := json.NewEncoder(w)
+	for _, i := range intervals {
+		if err := enc.Encode(NewRecord(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
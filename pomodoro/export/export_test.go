@@ -0,0 +1,175 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/export"
+)
+
+// Интервалы для тестов выгрузки
+func testIntervals() []pomodoro.Interval {
+	start := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	return []pomodoro.Interval{
+		{
+			ID:              1,
+			StartTime:       start,
+			PlannedDuration: 25 * time.Minute,
+			ActualDuration:  27 * time.Minute,
+			Category:        pomodoro.CategoryPomodoro,
+			State:           pomodoro.StateDone,
+			Profile:         pomodoro.DefaultProfile,
+			Task:            "Отчёт, черновик",
+			Tags:            []string{"work", "docs"},
+			Pauses: []pomodoro.PauseSegment{
+				{Start: start.Add(10 * time.Minute), End: start.Add(13 * time.Minute)},
+			},
+		},
+		{
+			ID:              2,
+			StartTime:       start.Add(30 * time.Minute),
+			PlannedDuration: 5 * time.Minute,
+			ActualDuration:  90 * time.Second,
+			Category:        pomodoro.CategoryShortBreak,
+			Break:           true,
+			State:           pomodoro.StateCancelled,
+			Profile:         pomodoro.DefaultProfile,
+		},
+	}
+}
+
+func TestCSV(t *testing.T) {
+	expect := `id,start_time,end_time,category,break,state,profile,task,tags,planned_seconds,actual_seconds,overtime_seconds,pauses,paused_seconds,wall_end_time
+1,2025-03-14T09:00:00Z,2025-03-14T09:27:00Z,Pomodoro,false,done,default,"Отчёт, черновик",work;docs,1500,1620,120,1,180,2025-03-14T09:30:00Z
+2,2025-03-14T09:30:00Z,2025-03-14T09:31:30Z,ShortBreak,true,cancelled,default,,,300,90,0,0,0,2025-03-14T09:31:30Z
+`
+	var out bytes.Buffer
+	if err := export.Write(&out, export.FormatCSV, testIntervals()); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != expect {
+		t.Errorf("Ожидали:\n%s\nа получили:\n%s", expect, out.String())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := export.Write(&out, export.FormatJSON, testIntervals()); err != nil {
+		t.Fatal(err)
+	}
+
+	var records []map[string]any
+	if err := json.Unmarshal(out.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Ожидали записей: 2, а получили: %d", len(records))
+	}
+
+	// Имена полей совпадают с колонками CSV
+	for _, c := range export.Columns {
+		if _, ok := records[0][c]; !ok {
+			t.Errorf("В записи нет поля %q", c)
+		}
+	}
+	if tags, ok := records[1]["tags"].([]any); !ok || len(tags) != 0 {
+		t.Errorf("Ожидали пустой массив меток, а получили: %v", records[1]["tags"])
+	}
+}
+
+func TestJSONLines(t *testing.T) {
+	var out bytes.Buffer
+	if err := export.Write(&out, export.FormatJSONLines, testIntervals()); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Ожидали строк: 2, а получили: %d", len(lines))
+	}
+
+	var r export.Record
+	if err := json.Unmarshal([]byte(lines[1]), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != 2 || r.State != "cancelled" || r.ActualSeconds != 90 {
+		t.Errorf("Неожиданная запись: %+v", r)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	err := export.Write(&bytes.Buffer{}, "xml", testIntervals())
+	if !errors.Is(err, export.ErrUnknownFormat) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", export.ErrUnknownFormat, err)
+	}
+}
+
+func TestICS(t *testing.T) {
+	intervals := testIntervals()
+	// Исполняющийся интервал в календарь не попадает
+	intervals = append(intervals, pomodoro.Interval{
+		ID:        3,
+		StartTime: time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC),
+		Category:  pomodoro.CategoryPomodoro,
+		State:     pomodoro.StateRunning,
+	})
+	// Длинная задача - проверяем перенос строк
+	intervals[0].Task = strings.Repeat("Очень длинная задача; ", 5)
+
+	testCases := []struct {
+		name      string
+		opts      export.ICSOptions
+		expEvents int
+	}{
+		{name: "DoneOnly", expEvents: 1},
+		{name: "WithCancelled", opts: export.ICSOptions{Cancelled: true}, expEvents: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := export.ICS(&out, intervals, tc.opts); err != nil {
+				t.Fatal(err)
+			}
+			ics := out.String()
+
+			lines := strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n")
+			for _, l := range lines {
+				if len(l) > 75 {
+					t.Errorf("Строка длиннее 75 байт: %q", l)
+				}
+			}
+			if n := strings.Count(ics, "BEGIN:VEVENT\r\n"); n != tc.expEvents {
+				t.Errorf("Ожидали событий: %d, а получили: %d", tc.expEvents, n)
+			}
+
+			// После склейки продолжений - исходные значения
+			unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+			for _, exp := range []string{
+				"UID:pomo-1@pomo\r\n",
+				"DTSTART:20250314T090000Z\r\n",
+				"DTEND:20250314T092700Z\r\n",
+				// Трёхминутная пауза - в описании
+				`Пауз: 1 на 3m0s\nОкончание с паузами: 2025-03-14T09:30:00Z`,
+				`SUMMARY:Pomodoro: Очень длинная задача\; Очень`,
+				"CATEGORIES:Pomodoro,work,docs\r\n",
+				"STATUS:CONFIRMED\r\n",
+			} {
+				if !strings.Contains(unfolded, exp) {
+					t.Errorf("Ожидали в выгрузке %q:\n%s", exp, unfolded)
+				}
+			}
+			if strings.Contains(ics, "UID:pomo-3@") {
+				t.Error("Исполняющийся интервал не должен выгружаться")
+			}
+			if tc.opts.Cancelled && !strings.Contains(ics, "UID:pomo-2@pomo\r\nDTSTAMP:20250314T093130Z") {
+				t.Errorf("Ожидали отменённый интервал с DTSTAMP по окончанию:\n%s", ics)
+			}
+		})
+	}
+}
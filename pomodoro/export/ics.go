@@ -0,0 +1,143 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Формат времени iCalendar в UTC
+const icsTimeLayout = "20060102T150405Z"
+
+// Домен в UID событий по умолчанию
+const DefaultUIDDomain = "pomo"
+
+// Настройки выгрузки в iCalendar
+type ICSOptions struct {
+	// Выгружать и отменённые интервалы - по умолчанию только завершённые
+	Cancelled bool
+	// Домен в UID: pomo-<ID>@<домен>. Если календари нескольких человек
+	// сводят вместе, у каждого должен быть свой домен.
+	UIDDomain string
+}
+
+// Выгружает интервалы в iCalendar (RFC 5545) - по событию VEVENT на интервал.
+// Исполняющиеся и не начатые интервалы не выгружаются. UID события зависит
+// только от ID интервала, так что повторная выгрузка обновляет события
+// в календаре, а не дублирует их.
+func ICS(w io.Writer, intervals []pomodoro.Interval, opts ICSOptions) error {
+	if opts.UIDDomain == "" {
+		opts.UIDDomain = DefaultUIDDomain
+	}
+
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeICSLine(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//vegorov.ru//pomo//RU")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", "pomo")
+
+	for _, i := range intervals {
+		status := "CONFIRMED"
+		switch {
+		case i.State == pomodoro.StateDone:
+		case i.State == pomodoro.StateCancelled && opts.Cancelled:
+			status = "CANCELLED"
+		default:
+			continue
+		}
+
+		start := i.StartTime.UTC()
+		end := start.Add(i.ActualDuration)
+
+		line("BEGIN", "VEVENT")
+		line("UID", fmt.Sprintf("pomo-%d@%s", i.ID, opts.UIDDomain))
+		// Метка времени события - его окончание, а не момент выгрузки:
+		// иначе каждая выгрузка меняла бы все события
+		line("DTSTAMP", end.Format(icsTimeLayout))
+		line("DTSTART", start.Format(icsTimeLayout))
+		line("DTEND", end.Format(icsTimeLayout))
+		line("SUMMARY", escapeICSText(summary(i)))
+		line("DESCRIPTION", escapeICSText(description(i)))
+		categories := []string{escapeICSText(i.Category)}
+		for _, t := range i.Tags {
+			categories = append(categories, escapeICSText(t))
+		}
+		line("CATEGORIES", strings.Join(categories, ","))
+		line("STATUS", status)
+		// Перерывы не занимают время в календаре
+		if i.Break {
+			line("TRANSP", "TRANSPARENT")
+		} else {
+			line("TRANSP", "OPAQUE")
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// Заголовок события: категория и задача, если она есть
+func summary(i pomodoro.Interval) string {
+	if i.Task == "" {
+		return i.Category
+	}
+	return i.Category + ": " + i.Task
+}
+
+func description(i pomodoro.Interval) string {
+	d := fmt.Sprintf("Запланировано: %s\nФактически: %s",
+		i.PlannedDuration.Round(time.Second), i.ActualDuration.Round(time.Second))
+	if o := i.Overtime(); o > 0 {
+		d += fmt.Sprintf("\nПереработка: %s", o.Round(time.Second))
+	}
+	if len(i.Pauses) > 0 {
+		d += fmt.Sprintf("\nПауз: %d на %s\nОкончание с паузами: %s", len(i.Pauses),
+			i.PausedTime().Round(time.Second), i.StartTime.Add(i.WallClock()).Format(time.RFC3339))
+	}
+	if i.Profile != "" {
+		d += "\nПрофиль: " + i.Profile
+	}
+	return d
+}
+
+// Экранирование текстовых значений по RFC 5545
+func escapeICSText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// Пишет строку с переносом по 75 байт (продолжение начинается с пробела)
+// и окончанием CRLF. Многобайтовые символы UTF-8 не разрываются.
+func writeICSLine(w *bufio.Writer, s string) {
+	// Первая строка - до 75 байт, продолжения - до 74 плюс пробел
+	limit := 75
+	for len(s) > limit {
+		n := limit
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		w.WriteString(s[:n])
+		w.WriteString("\r\n ")
+		s = s[n:]
+		limit = 74
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,92 @@
+package pomodoro
+
+import (
+	"fmt"
+	"time"
+)
+
+// Минимальный перерыв в режиме Flowtime. Перерыв с нулевой продолжительностью
+// превратился бы в открытый интервал, поэтому короче этого не бывает.
+const MinFlowtimeBreak = time.Minute
+
+// Строка таблицы коэффициентов Flowtime: для работы продолжительностью
+// до UpTo включительно перерыв составит Ratio от времени работы.
+// UpTo == 0 - без ограничения сверху (последняя строка таблицы).
+type FlowtimeRatio struct {
+	UpTo  time.Duration
+	Ratio float64
+}
+
+// Таблица коэффициентов по умолчанию - примерно соответствует
+// классическим рекомендациям Flowtime:
+// до 25 мин - 5 мин, до 50 мин - 8 мин, до 90 мин - 10 мин, дольше - 15 мин и больше
+func DefaultFlowtimeRatios() []FlowtimeRatio {
+	return []FlowtimeRatio{
+		{UpTo: 25 * time.Minute, Ratio: 0.2},
+		{UpTo: 50 * time.Minute, Ratio: 0.16},
+		{UpTo: 90 * time.Minute, Ratio: 0.11},
+		{UpTo: 0, Ratio: 0.17},
+	}
+}
+
+// Проверяет таблицу коэффициентов: строки по возрастанию UpTo,
+// коэффициенты положительные, строка без ограничения - только последней
+func ValidateFlowtimeRatios(ratios []FlowtimeRatio) error {
+	if len(ratios) == 0 {
+		return fmt.Errorf("%w: таблица пуста", ErrInvalidRatios)
+	}
+	var prev time.Duration
+	for k, r := range ratios {
+		if r.Ratio <= 0 {
+			return fmt.Errorf("%w: коэффициент должен быть положительным: %v",
+				ErrInvalidRatios, r.Ratio)
+		}
+		if r.UpTo == 0 && k != len(ratios)-1 {
+			return fmt.Errorf("%w: строка без ограничения должна быть последней",
+				ErrInvalidRatios)
+		}
+		if r.UpTo != 0 && r.UpTo <= prev {
+			return fmt.Errorf("%w: строки должны идти по возрастанию: %v",
+				ErrInvalidRatios, r.UpTo)
+		}
+		prev = r.UpTo
+	}
+	return nil
+}
+
+// Вычисляет продолжительность перерыва для отработанного времени work.
+// Если работа дольше всех строк таблицы - используется последняя строка.
+func FlowtimeBreak(ratios []FlowtimeRatio, work time.Duration) time.Duration {
+	if len(ratios) == 0 {
+		ratios = DefaultFlowtimeRatios()
+	}
+
+	ratio := ratios[len(ratios)-1].Ratio
+	for _, r := range ratios {
+		if r.UpTo == 0 || work <= r.UpTo {
+			ratio = r.Ratio
+			break
+		}
+	}
+
+	b := time.Duration(float64(work) * ratio).Round(time.Second)
+	if b < MinFlowtimeBreak {
+		return MinFlowtimeBreak
+	}
+	return b
+}
+
+// Планирует следующий интервал в режиме Flowtime: работа - открытый интервал,
+// перерыв - пропорционален продолжительности последней работы
+func (p Profile) planFlowtime(recent []Interval) Interval {
+	i := Interval{Category: CategoryPomodoro}
+
+	// После работы - перерыв, после перерыва (или в самом начале) - работа.
+	// Длинных перерывов во Flowtime нет - длину перерыва определяет работа.
+	if len(recent) > 0 && !recent[0].Break {
+		i.Category = CategoryShortBreak
+		i.Break = true
+		i.PlannedDuration = FlowtimeBreak(p.FlowtimeRatios, recent[0].ActualDuration)
+	}
+	return i
+}
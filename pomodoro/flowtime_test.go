@@ -0,0 +1,118 @@
+package pomodoro_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestFlowtimeBreak(t *testing.T) {
+	ratios := pomodoro.DefaultFlowtimeRatios()
+
+	testCases := []struct {
+		name   string
+		work   time.Duration
+		expect time.Duration
+	}{
+		{name: "Minimum", work: 2 * time.Minute, expect: pomodoro.MinFlowtimeBreak},
+		{name: "UpTo25", work: 25 * time.Minute, expect: 5 * time.Minute},
+		{name: "UpTo50", work: 50 * time.Minute, expect: 8 * time.Minute},
+		{name: "UpTo90", work: 90 * time.Minute, expect: 9*time.Minute + 54*time.Second},
+		{name: "Unlimited", work: 120 * time.Minute, expect: 20*time.Minute + 24*time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := pomodoro.FlowtimeBreak(ratios, tc.work)
+			if b != tc.expect {
+				t.Errorf("Ожидали перерыв: %q, а получили: %q", tc.expect, b)
+			}
+		})
+	}
+}
+
+func TestValidateFlowtimeRatios(t *testing.T) {
+	testCases := []struct {
+		name   string
+		ratios []pomodoro.FlowtimeRatio
+		expErr error
+	}{
+		{name: "Default", ratios: pomodoro.DefaultFlowtimeRatios()},
+		{name: "Empty", ratios: nil, expErr: pomodoro.ErrInvalidRatios},
+		{
+			name:   "NegativeRatio",
+			ratios: []pomodoro.FlowtimeRatio{{UpTo: 0, Ratio: -1}},
+			expErr: pomodoro.ErrInvalidRatios,
+		},
+		{
+			name: "UnlimitedNotLast",
+			ratios: []pomodoro.FlowtimeRatio{
+				{UpTo: 0, Ratio: 0.2},
+				{UpTo: time.Hour, Ratio: 0.2},
+			},
+			expErr: pomodoro.ErrInvalidRatios,
+		},
+		{
+			name: "NotSorted",
+			ratios: []pomodoro.FlowtimeRatio{
+				{UpTo: time.Hour, Ratio: 0.2},
+				{UpTo: time.Minute, Ratio: 0.2},
+			},
+			expErr: pomodoro.ErrInvalidRatios,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := pomodoro.ValidateFlowtimeRatios(tc.ratios)
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("Ожидали ошибку: %v, а получили: %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestFlowtimeInterval(t *testing.T) {
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, 0, 0, 0)
+	config.Mode = pomodoro.ModeFlowtime
+	// Перерыв в 60 раз длиннее работы - чтобы секунда работы дала минуту перерыва
+	config.FlowtimeRatios = []pomodoro.FlowtimeRatio{{UpTo: 0, Ratio: 60}}
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.Category != pomodoro.CategoryPomodoro || !i.OpenEnded() {
+		t.Fatalf("Ожидали открытый интервал %q, а получили: %+v", pomodoro.CategoryPomodoro, i)
+	}
+
+	start := func(pomodoro.Interval) {}
+	end := func(pomodoro.Interval) {}
+	periodic := func(i pomodoro.Interval) {
+		// Открытый интервал сам не завершится - завершаем явно
+		if err := i.Finish(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := i.Start(context.Background(), config, start, periodic, end); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Category != pomodoro.CategoryShortBreak {
+		t.Errorf("Ожидали категорию: %q, а получили: %q", pomodoro.CategoryShortBreak, b.Category)
+	}
+	if b.PlannedDuration != time.Minute {
+		t.Errorf("Ожидали продолжительность: %q, а получили: %q", time.Minute, b.PlannedDuration)
+	}
+}
@@ -0,0 +1,228 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func init() {
+	Register("csv", newCSVImporter)
+}
+
+// Поля интервала, которые можно сопоставить колонкам CSV
+const (
+	fieldStart    = "start"
+	fieldEnd      = "end"
+	fieldDuration = "duration"
+	fieldPlanned  = "planned"
+	fieldCategory = "category"
+	fieldBreak    = "break"
+	fieldState    = "state"
+	fieldProfile  = "profile"
+	fieldTask     = "task"
+	fieldTags     = "tags"
+)
+
+// Соответствие по умолчанию - колонки нашей же CSV-выгрузки
+var defaultMapping = map[string]string{
+	fieldStart:    "start_time",
+	fieldEnd:      "end_time",
+	fieldDuration: "actual_seconds",
+	fieldPlanned:  "planned_seconds",
+	fieldCategory: "category",
+	fieldBreak:    "break",
+	fieldState:    "state",
+	fieldProfile:  "profile",
+	fieldTask:     "task",
+	fieldTags:     "tags",
+}
+
+// Форматы времени без пояса, которые понимает импорт CSV
+var timeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+}
+
+// Импорт из произвольного CSV с заголовком. Какие колонки чему соответствуют,
+// задаётся в Options.Mapping (поле интервала -> имя колонки) поверх
+// соответствия по умолчанию. Обязательно время начала, и ещё нужно
+// время окончания или продолжительность.
+type csvImporter struct {
+	opts    Options
+	mapping map[string]string
+}
+
+func newCSVImporter(opts Options) (Importer, error) {
+	mapping := map[string]string{}
+	for f, c := range defaultMapping {
+		mapping[f] = c
+	}
+	for f, c := range opts.Mapping {
+		if _, ok := defaultMapping[f]; !ok {
+			return nil, fmt.Errorf("%w: неизвестное поле %q в соответствии колонок", ErrInvalidData, f)
+		}
+		mapping[f] = c
+	}
+	return csvImporter{opts: opts, mapping: mapping}, nil
+}
+
+func (c csvImporter) Parse(r io.Reader) ([]pomodoro.Interval, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+
+	// Номер колонки для каждого поля; поля без колонки в файле пропускаем
+	columns := map[string]int{}
+	for f, name := range c.mapping {
+		for k, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				columns[f] = k
+			}
+		}
+	}
+	if _, ok := columns[fieldStart]; !ok {
+		return nil, fmt.Errorf("%w: нет колонки %q с временем начала", ErrInvalidData, c.mapping[fieldStart])
+	}
+	_, hasEnd := columns[fieldEnd]
+	_, hasDuration := columns[fieldDuration]
+	if !hasEnd && !hasDuration {
+		return nil, fmt.Errorf("%w: нужна колонка %q или %q", ErrInvalidData,
+			c.mapping[fieldEnd], c.mapping[fieldDuration])
+	}
+
+	intervals := []pomodoro.Interval{}
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+		}
+
+		i, err := c.parseRow(row, columns)
+		if err != nil {
+			return nil, fmt.Errorf("строка %d: %w", line, err)
+		}
+		intervals = append(intervals, i)
+	}
+	return intervals, nil
+}
+
+func (c csvImporter) parseRow(row []string, columns map[string]int) (pomodoro.Interval, error) {
+	value := func(f string) string {
+		k, ok := columns[f]
+		if !ok || k >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[k])
+	}
+
+	i := pomodoro.Interval{Category: c.opts.Category, State: pomodoro.StateDone}
+
+	var err error
+	if i.StartTime, err = c.parseTime(value(fieldStart)); err != nil {
+		return i, err
+	}
+
+	if v := value(fieldDuration); v != "" {
+		if i.ActualDuration, err = parseDuration(v); err != nil {
+			return i, err
+		}
+	} else {
+		end, err := c.parseTime(value(fieldEnd))
+		if err != nil {
+			return i, err
+		}
+		i.ActualDuration = end.Sub(i.StartTime)
+	}
+	if i.ActualDuration < 0 {
+		return i, fmt.Errorf("%w: окончание раньше начала", ErrInvalidData)
+	}
+
+	if v := value(fieldPlanned); v != "" {
+		if i.PlannedDuration, err = parseDuration(v); err != nil {
+			return i, err
+		}
+	}
+	if v := value(fieldCategory); v != "" {
+		i.Category = v
+	}
+	if v := value(fieldBreak); v != "" {
+		if i.Break, err = strconv.ParseBool(v); err != nil {
+			return i, fmt.Errorf("%w: break: %v", ErrInvalidData, err)
+		}
+	}
+	if v := value(fieldState); v != "" {
+		if i.State, err = pomodoro.ParseState(v); err != nil {
+			return i, err
+		}
+	}
+	i.Profile = value(fieldProfile)
+	i.Task = value(fieldTask)
+	i.Tags = splitTags(value(fieldTags))
+
+	return normalize(i), nil
+}
+
+// Разбирает время: RFC 3339, форматы timeLayouts в часовом поясе импорта
+// или Unix-время в секундах
+func (c csvImporter) parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, fmt.Errorf("%w: пустое время", ErrInvalidData)
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, l := range timeLayouts {
+		if t, err := time.ParseInLocation(l, s, c.opts.Location); err == nil {
+			return t, nil
+		}
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: неизвестный формат времени %q", ErrInvalidData, s)
+}
+
+// Разбирает продолжительность: целое число секунд, формат Go (25m) или h:mm:ss
+func parseDuration(s string) (time.Duration, error) {
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(sec) * time.Second, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	var h, m, sec int
+	if n, err := fmt.Sscanf(s, "%d:%d:%d", &h, &m, &sec); err == nil && n == 3 {
+		return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+			time.Duration(sec)*time.Second, nil
+	}
+	return 0, fmt.Errorf("%w: неизвестный формат продолжительности %q", ErrInvalidData, s)
+}
+
+// Метки разделены точкой с запятой (как в нашей выгрузке) или запятой
+func splitTags(s string) []string {
+	var tags []string
+	for _, t := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == ',' }) {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,159 @@
+// Импорт истории интервалов из других Pomodoro-таймеров.
+//
+// Импортёры подключаемые: каждый формат регистрируется под своим именем
+// через Register, команда pomo import выбирает его по имени.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Ошибки
+var (
+	ErrUnknownFormat = errors.New("неизвестный формат импорта")
+	ErrInvalidData   = errors.New("неверные данные для импорта")
+)
+
+// Импортёр разбирает данные другого формата в интервалы
+type Importer interface {
+	Parse(r io.Reader) ([]pomodoro.Interval, error)
+}
+
+// Настройки импортёра - каждый формат использует те, что ему нужны
+type Options struct {
+	// Категория для интервалов, у которых в исходных данных её нет
+	Category string
+	// Соответствие полей интервала колонкам исходных данных (для CSV)
+	Mapping map[string]string
+	// Часовой пояс для времени без указания пояса
+	Location *time.Location
+}
+
+// Создаёт импортёр по настройкам
+type Factory func(opts Options) (Importer, error)
+
+var registry = map[string]Factory{}
+
+// Регистрирует формат импорта под именем name
+func Register(name string, f Factory) {
+	registry[name] = f
+}
+
+// Зарегистрированные форматы по алфавиту
+func Formats() []string {
+	return slices.Sorted(maps.Keys(registry))
+}
+
+// Создаёт импортёр формата name
+func New(name string, opts Options) (Importer, error) {
+	f, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q, допустимо: %s", ErrUnknownFormat, name,
+			strings.Join(Formats(), ", "))
+	}
+	if opts.Category == "" {
+		opts.Category = pomodoro.CategoryPomodoro
+	}
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	return f(opts)
+}
+
+// Итог импорта
+type Result struct {
+	// Всего интервалов в исходных данных
+	Total int
+	// Импортировано (или было бы импортировано при dryRun)
+	Imported []pomodoro.Interval
+	// Пропущено как дубликаты - уже есть в репозитории или повторяются в данных
+	Duplicates int
+}
+
+// Ключ для поиска дубликатов: время начала с точностью до секунды и категория
+type dedupKey struct {
+	start    int64
+	category string
+}
+
+func keyOf(i pomodoro.Interval) dedupKey {
+	return dedupKey{start: i.StartTime.Unix(), category: i.Category}
+}
+
+// Создаёт интервалы в репозитории, пропуская дубликаты. Пишет их в репозиторий
+// разом - файл с историей переписывается один раз, а не на каждый интервал.
+// При dryRun в репозиторий ничего не пишется - только считается итог.
+func Import(repo pomodoro.Repository, intervals []pomodoro.Interval, dryRun bool) (Result, error) {
+	res := Result{Total: len(intervals)}
+	if len(intervals) == 0 {
+		return res, nil
+	}
+
+	// Существующие интервалы в промежутке импортируемых данных
+	from, to := intervals[0].StartTime, intervals[0].StartTime
+	for _, i := range intervals {
+		if i.StartTime.Before(from) {
+			from = i.StartTime
+		}
+		if i.StartTime.After(to) {
+			to = i.StartTime
+		}
+	}
+	existing, err := repo.Range(from.Truncate(time.Second), to.Add(time.Second))
+	if err != nil {
+		return res, err
+	}
+
+	seen := map[dedupKey]bool{}
+	for _, i := range existing {
+		seen[keyOf(i)] = true
+	}
+
+	for _, i := range intervals {
+		k := keyOf(i)
+		if seen[k] {
+			res.Duplicates++
+			continue
+		}
+		seen[k] = true
+		res.Imported = append(res.Imported, i)
+	}
+	if dryRun || len(res.Imported) == 0 {
+		return res, nil
+	}
+
+	ids, err := pomodoro.CreateMany(repo, res.Imported)
+	if err != nil {
+		return res, err
+	}
+	for k, id := range ids {
+		res.Imported[k].ID = id
+	}
+	return res, nil
+}
+
+// Приводит разобранный интервал к виду, пригодному для репозитория:
+// без ID, с известной продолжительностью, и обязательно завершённый -
+// исполняющийся чужой интервал стал бы "текущим" в pomo
+func normalize(i pomodoro.Interval) pomodoro.Interval {
+	i.ID = 0
+	if i.PlannedDuration == 0 {
+		i.PlannedDuration = i.ActualDuration
+	}
+	if i.State != pomodoro.StateDone {
+		i.State = pomodoro.StateCancelled
+	}
+	// Для классических категорий знаем, перерыв это или нет
+	if i.Category == pomodoro.CategoryShortBreak || i.Category == pomodoro.CategoryLongBreak {
+		i.Break = true
+	}
+	return i
+}
@@ -0,0 +1,252 @@
+package importer_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/export"
+	"vegorov.ru/go-cli/pomo/pomodoro/importer"
+	"vegorov.ru/go-cli/pomo/pomodoro/repository"
+)
+
+func parse(t *testing.T, format string, opts importer.Options, data string) []pomodoro.Interval {
+	t.Helper()
+	imp, err := importer.New(format, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intervals, err := imp.Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return intervals
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	start := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	source := []pomodoro.Interval{
+		{
+			ID:              7,
+			StartTime:       start,
+			PlannedDuration: 25 * time.Minute,
+			ActualDuration:  27 * time.Minute,
+			Category:        pomodoro.CategoryPomodoro,
+			State:           pomodoro.StateDone,
+			Profile:         pomodoro.DefaultProfile,
+			Task:            "Отчёт",
+			Tags:            []string{"work", "docs"},
+		},
+		{
+			ID:              8,
+			StartTime:       start.Add(30 * time.Minute),
+			PlannedDuration: 5 * time.Minute,
+			ActualDuration:  time.Minute,
+			Category:        pomodoro.CategoryShortBreak,
+			Break:           true,
+			State:           pomodoro.StateCancelled,
+		},
+	}
+
+	for _, format := range []string{export.FormatJSON, export.FormatJSONLines, export.FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := export.Write(&buf, format, source); err != nil {
+				t.Fatal(err)
+			}
+			in := "json"
+			if format == export.FormatCSV {
+				in = "csv"
+			}
+			got := parse(t, in, importer.Options{}, buf.String())
+			if len(got) != len(source) {
+				t.Fatalf("Ожидали интервалов: %d, а получили: %d", len(source), len(got))
+			}
+			for k, i := range got {
+				exp := source[k]
+				exp.ID = 0
+				if !i.StartTime.Equal(exp.StartTime) || i.ActualDuration != exp.ActualDuration ||
+					i.PlannedDuration != exp.PlannedDuration || i.Category != exp.Category ||
+					i.State != exp.State || i.Break != exp.Break || i.Task != exp.Task ||
+					strings.Join(i.Tags, ";") != strings.Join(exp.Tags, ";") {
+					t.Errorf("\nОжидали: %+v,\nполучили: %+v", exp, i)
+				}
+			}
+		})
+	}
+}
+
+func TestCSVMapping(t *testing.T) {
+	data := `When,Minutes,Kind,What
+2025-03-14 09:00,25m,Pomodoro,Отчёт
+2025-03-14 09:25,300,ShortBreak,
+`
+	opts := importer.Options{
+		Mapping:  map[string]string{"start": "When", "duration": "Minutes", "category": "Kind", "task": "What"},
+		Location: time.UTC,
+	}
+	got := parse(t, "csv", opts, data)
+	if len(got) != 2 {
+		t.Fatalf("Ожидали интервалов: 2, а получили: %d", len(got))
+	}
+	if got[0].ActualDuration != 25*time.Minute || got[0].PlannedDuration != 25*time.Minute ||
+		got[0].Task != "Отчёт" || got[0].State != pomodoro.StateDone {
+		t.Errorf("Неверный первый интервал: %+v", got[0])
+	}
+	if !got[1].Break || got[1].ActualDuration != 5*time.Minute ||
+		!got[1].StartTime.Equal(time.Date(2025, 3, 14, 9, 25, 0, 0, time.UTC)) {
+		t.Errorf("Неверный второй интервал: %+v", got[1])
+	}
+
+	// Нет ни окончания, ни продолжительности
+	imp, err := importer.New("csv", importer.Options{Mapping: map[string]string{"start": "When"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := imp.Parse(strings.NewReader("When\n2025-03-14 09:00\n")); !errors.Is(err, importer.ErrInvalidData) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", importer.ErrInvalidData, err)
+	}
+
+	// Неизвестное поле в соответствии
+	if _, err := importer.New("csv", importer.Options{Mapping: map[string]string{"foo": "bar"}}); !errors.Is(err, importer.ErrInvalidData) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", importer.ErrInvalidData, err)
+	}
+}
+
+func TestTimewarrior(t *testing.T) {
+	data := `inc 20250314T090000Z - 20250314T092500Z # work "deep focus" # Отчёт за квартал
+inc 20250314T100000Z - 20250314T101000Z
+inc 20250314T110000Z # work
+`
+	got := parse(t, "timewarrior", importer.Options{Category: "Deep"}, data)
+	if len(got) != 2 {
+		t.Fatalf("Ожидали интервалов: 2 (незакрытый пропускаем), а получили: %d", len(got))
+	}
+
+	i := got[0]
+	if !i.StartTime.Equal(time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)) ||
+		i.ActualDuration != 25*time.Minute || i.Category != "Deep" ||
+		i.Task != "Отчёт за квартал" || strings.Join(i.Tags, ";") != "work;deep focus" {
+		t.Errorf("Неверный интервал: %+v", i)
+	}
+
+	imp, err := importer.New("timewarrior", importer.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := imp.Parse(strings.NewReader("exc 20250314T090000Z\n")); !errors.Is(err, importer.ErrInvalidData) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", importer.ErrInvalidData, err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	start := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	repo := repository.NewInMemoryRepo()
+	if _, err := repo.Create(pomodoro.Interval{
+		StartTime: start, Category: pomodoro.CategoryPomodoro, State: pomodoro.StateDone,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	intervals := []pomodoro.Interval{
+		// Уже есть в репозитории
+		{StartTime: start, Category: pomodoro.CategoryPomodoro, State: pomodoro.StateDone},
+		// То же время, другая категория - не дубликат
+		{StartTime: start, Category: pomodoro.CategoryShortBreak, State: pomodoro.StateDone},
+		{StartTime: start.Add(time.Hour), Category: pomodoro.CategoryPomodoro, State: pomodoro.StateDone},
+		// Повтор внутри данных
+		{StartTime: start.Add(time.Hour), Category: pomodoro.CategoryPomodoro, State: pomodoro.StateDone},
+	}
+
+	testCases := []struct {
+		name     string
+		dryRun   bool
+		expTotal int
+	}{
+		{name: "DryRun", dryRun: true, expTotal: 1},
+		{name: "Import", dryRun: false, expTotal: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := importer.Import(repo, intervals, tc.dryRun)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Total != 4 || len(res.Imported) != 2 || res.Duplicates != 2 {
+				t.Errorf("Ожидали 4/2/2, а получили: %d/%d/%d",
+					res.Total, len(res.Imported), res.Duplicates)
+			}
+
+			stored, err := repo.Range(start, start.Add(2*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(stored) != tc.expTotal {
+				t.Errorf("Ожидали в репозитории: %d, а получили: %d", tc.expTotal, len(stored))
+			}
+		})
+	}
+
+	// Повторный импорт ничего не добавляет
+	res, err := importer.Import(repo, intervals, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Imported) != 0 {
+		t.Errorf("Ожидали повторный импорт пустым, а получили: %d", len(res.Imported))
+	}
+}
+
+// Репозиторий, который считает записи
+type countingRepo struct {
+	pomodoro.Repository
+	creates, bulks int
+}
+
+func (r *countingRepo) Create(i pomodoro.Interval) (int64, error) {
+	r.creates++
+	return r.Repository.Create(i)
+}
+
+func (r *countingRepo) CreateMany(intervals []pomodoro.Interval) ([]int64, error) {
+	r.bulks++
+	return pomodoro.CreateMany(r.Repository, intervals)
+}
+
+// Импорт пишет в репозиторий одной записью, а не по интервалу
+func TestImportBulk(t *testing.T) {
+	repo := &countingRepo{Repository: repository.NewInMemoryRepo()}
+
+	start := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	intervals := make([]pomodoro.Interval, 0, 100)
+	for k := range 100 {
+		intervals = append(intervals, pomodoro.Interval{
+			StartTime: start.Add(time.Duration(k) * time.Hour),
+			Category:  pomodoro.CategoryPomodoro,
+			State:     pomodoro.StateDone,
+		})
+	}
+
+	res, err := importer.Import(repo, intervals, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.bulks != 1 || repo.creates != 0 {
+		t.Errorf("Ожидали одну запись разом, а получили: разом %d, по одному %d", repo.bulks, repo.creates)
+	}
+	for k, i := range res.Imported {
+		if i.ID != int64(k)+1 {
+			t.Fatalf("Ожидали ID %d, а получили: %d", k+1, i.ID)
+		}
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	if _, err := importer.New("toggl", importer.Options{}); !errors.Is(err, importer.ErrUnknownFormat) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", importer.ErrUnknownFormat, err)
+	}
+}
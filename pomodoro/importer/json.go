@@ -0,0 +1,102 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/export"
+)
+
+func init() {
+	Register("json", func(Options) (Importer, error) { return jsonImporter{}, nil })
+}
+
+// Импорт из нашей же выгрузки: JSON-массив или JSON Lines
+type jsonImporter struct{}
+
+func (jsonImporter) Parse(r io.Reader) ([]pomodoro.Interval, error) {
+	br := bufio.NewReader(r)
+
+	// Массив или JSON Lines - смотрим на первый значащий символ
+	var records []export.Record
+	first, err := peekNonSpace(br)
+	if err != nil {
+		return nil, err
+	}
+	if first == '[' {
+		if err := json.NewDecoder(br).Decode(&records); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+		}
+	} else {
+		dec := json.NewDecoder(br)
+		for {
+			var rec export.Record
+			err := dec.Decode(&rec)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+			}
+			records = append(records, rec)
+		}
+	}
+
+	intervals := make([]pomodoro.Interval, 0, len(records))
+	for k, rec := range records {
+		i, err := fromRecord(rec)
+		if err != nil {
+			return nil, fmt.Errorf("запись %d: %w", k+1, err)
+		}
+		intervals = append(intervals, i)
+	}
+	return intervals, nil
+}
+
+// Интервал из записи выгрузки
+func fromRecord(rec export.Record) (pomodoro.Interval, error) {
+	start, err := time.Parse(time.RFC3339, rec.StartTime)
+	if err != nil {
+		return pomodoro.Interval{}, fmt.Errorf("%w: start_time: %v", ErrInvalidData, err)
+	}
+	state, err := pomodoro.ParseState(rec.State)
+	if err != nil {
+		return pomodoro.Interval{}, fmt.Errorf("%w: state: %v", ErrInvalidData, err)
+	}
+
+	return normalize(pomodoro.Interval{
+		StartTime:       start,
+		PlannedDuration: time.Duration(rec.PlannedSeconds) * time.Second,
+		ActualDuration:  time.Duration(rec.ActualSeconds) * time.Second,
+		Category:        rec.Category,
+		State:           state,
+		Break:           rec.Break,
+		Profile:         rec.Profile,
+		Task:            rec.Task,
+		Tags:            rec.Tags,
+	}), nil
+}
+
+// Первый непробельный байт без его извлечения из потока. Пустой поток - не ошибка.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err == io.EOF {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		if _, err := br.Discard(1); err != nil {
+			return 0, err
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func init() {
+	Register("timewarrior", func(opts Options) (Importer, error) {
+		return timewarriorImporter{opts: opts}, nil
+	})
+}
+
+// Формат времени в файлах данных Timewarrior - всегда UTC
+const timewarriorLayout = "20060102T150405Z"
+
+// Импорт из файлов данных Timewarrior (~/.timewarrior/data/YYYY-MM.data).
+// Строка файла:
+//
+//	inc 20240101T090000Z - 20240101T092500Z # tag "tag two" # annotation
+//
+// Метки становятся метками интервала, аннотация - задачей, категория
+// берётся из настроек. Незакрытый (текущий) интервал пропускаем.
+type timewarriorImporter struct {
+	opts Options
+}
+
+func (t timewarriorImporter) Parse(r io.Reader) ([]pomodoro.Interval, error) {
+	intervals := []pomodoro.Interval{}
+
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" {
+			continue
+		}
+
+		i, ok, err := t.parseLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("строка %d: %w", line, err)
+		}
+		if ok {
+			intervals = append(intervals, i)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return intervals, nil
+}
+
+// Разбирает строку; ok == false - строку нужно пропустить
+func (t timewarriorImporter) parseLine(text string) (i pomodoro.Interval, ok bool, err error) {
+	fields := splitQuoted(text)
+	if len(fields) < 2 || fields[0] != "inc" {
+		return i, false, fmt.Errorf("%w: ожидали inc в начале строки", ErrInvalidData)
+	}
+
+	start, err := time.Parse(timewarriorLayout, fields[1])
+	if err != nil {
+		return i, false, fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	rest := fields[2:]
+
+	// Без " - конец" интервал ещё исполняется в Timewarrior
+	if len(rest) < 2 || rest[0] != "-" {
+		return i, false, nil
+	}
+	end, err := time.Parse(timewarriorLayout, rest[1])
+	if err != nil {
+		return i, false, fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	if end.Before(start) {
+		return i, false, fmt.Errorf("%w: окончание раньше начала", ErrInvalidData)
+	}
+	rest = rest[2:]
+
+	// Дальше: # метки... [# аннотация]
+	var tags []string
+	var annotation []string
+	section := 0
+	for _, f := range rest {
+		if f == "#" {
+			section++
+			continue
+		}
+		switch section {
+		case 1:
+			tags = append(tags, f)
+		case 2:
+			annotation = append(annotation, f)
+		}
+	}
+
+	return normalize(pomodoro.Interval{
+		StartTime:      start,
+		ActualDuration: end.Sub(start),
+		Category:       t.opts.Category,
+		State:          pomodoro.StateDone,
+		Task:           strings.Join(annotation, " "),
+		Tags:           tags,
+	}), true, nil
+}
+
+// Делит строку по пробелам с учётом кавычек: "tag two" - одно поле
+func splitQuoted(s string) []string {
+	var fields []string
+	var b strings.Builder
+	quoted, escaped, inField := false, false, false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+			inField = true
+		case r == ' ' && !quoted:
+			if inField {
+				fields = append(fields, b.String())
+				b.Reset()
+				inField = false
+			}
+		default:
+			b.WriteRune(r)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, b.String())
+	}
+	return fields
+}
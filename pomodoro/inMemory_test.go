@@ -1,3 +1,5 @@
+//go:build inmemory
+
 package pomodoro_test
 
 import (
@@ -0,0 +1,69 @@
+package pomodoro
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Виды прерываний по технике Pomodoro
+//
+//	internal - отвлёкся сам: вспомнил о другом деле, потянуло в чат
+//	external - отвлекли другие: звонок, коллега, уведомление
+const (
+	InterruptionInternal = "internal"
+	InterruptionExternal = "external"
+)
+
+// Ошибки
+var ErrInvalidInterruption = errors.New("неизвестный вид прерывания")
+
+// Прерывание во время интервала. Интервал при этом не останавливается.
+type Interruption struct {
+	Time time.Time
+	Kind string
+	Note string
+}
+
+// Сколько прерываний вида kind было во время интервала
+func (i Interval) InterruptionCount(kind string) int {
+	n := 0
+	for _, in := range i.Interruptions {
+		if in.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Записывает прерывание текущего - исполняющегося или приостановленного - интервала.
+// Возвращает интервал с записанным прерыванием.
+func Interrupt(config *IntevalConfig, kind, note string) (Interval, error) {
+	if kind != InterruptionInternal && kind != InterruptionExternal {
+		return Interval{}, fmt.Errorf("%w: %q, допустимо: %s, %s",
+			ErrInvalidInterruption, kind, InterruptionInternal, InterruptionExternal)
+	}
+
+	i, err := config.repo.Last()
+	if errors.Is(err, ErrNoIntervals) {
+		return i, ErrIntervalNotRunning
+	}
+	if err != nil {
+		return i, err
+	}
+	i, err = config.update(i.ID, func(i *Interval) error {
+		if i.State != StateRunning && i.State != StatePaused {
+			return ErrIntervalNotRunning
+		}
+		// Clip - чтобы append не записал в общий с репозиторием массив
+		i.Interruptions = append(slices.Clip(i.Interruptions),
+			Interruption{Time: time.Now(), Kind: kind, Note: note})
+		return nil
+	})
+	if err != nil {
+		return i, err
+	}
+	config.emit(EventInterrupt, i)
+	return i, nil
+}
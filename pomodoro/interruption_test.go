@@ -0,0 +1,80 @@
+package pomodoro_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestInterrupt(t *testing.T) {
+	const duration = 2 * time.Second
+
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, duration, duration, duration)
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Интервал ещё не начат - прерывать нечего
+	if _, err := pomodoro.Interrupt(config, pomodoro.InterruptionInternal, ""); !errors.Is(err, pomodoro.ErrIntervalNotRunning) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrIntervalNotRunning, err)
+	}
+
+	testCases := []struct {
+		name   string
+		kind   string
+		note   string
+		expErr error
+	}{
+		{name: "Internal", kind: pomodoro.InterruptionInternal},
+		{name: "External", kind: pomodoro.InterruptionExternal, note: "звонок"},
+		{name: "Unknown", kind: "coffee", expErr: pomodoro.ErrInvalidInterruption},
+	}
+
+	start := func(pomodoro.Interval) {}
+	end := func(pomodoro.Interval) {}
+	periodic := func(pomodoro.Interval) {
+		// Прерывания записываем на первом тике - интервал продолжает идти
+		for _, tc := range testCases {
+			_, err := pomodoro.Interrupt(config, tc.kind, tc.note)
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("%s: ожидали ошибку: %v, а получили: %v", tc.name, tc.expErr, err)
+			}
+		}
+	}
+	// После первого тика прерывания уже записаны - дальше не пишем
+	once := func(i pomodoro.Interval) {
+		if len(i.Interruptions) == 0 {
+			periodic(i)
+		}
+	}
+
+	if err := i.Start(context.Background(), config, start, once, end); err != nil {
+		t.Fatal(err)
+	}
+
+	i, err = repo.ByID(i.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Прерывания не останавливают интервал
+	if i.State != pomodoro.StateDone || i.ActualDuration != duration {
+		t.Errorf("Ожидали завершённый интервал длиной %q, а получили: %d, %q",
+			duration, i.State, i.ActualDuration)
+	}
+	if len(i.Interruptions) != 2 ||
+		i.InterruptionCount(pomodoro.InterruptionInternal) != 1 ||
+		i.InterruptionCount(pomodoro.InterruptionExternal) != 1 {
+		t.Fatalf("Ожидали по одному прерыванию каждого вида, а получили: %+v", i.Interruptions)
+	}
+	if i.Interruptions[1].Note != "звонок" || i.Interruptions[1].Time.IsZero() {
+		t.Errorf("Неверное прерывание: %+v", i.Interruptions[1])
+	}
+}
@@ -10,16 +10,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 )
 
-// Категории (типы) интервалов
+// Категории (типы) интервалов классического расписания.
+// Собственные типы и их последовательность задаются через Schedule.
 const (
 	CategoryPomodoro   = "Pomodoro"
 	CategoryShortBreak = "ShortBreak"
 	CategoryLongBreak  = "LongBreak"
 )
 
+// Режимы работы
+//
+//	classic - классическое расписание с фиксированными продолжительностями
+//	flowtime - работа до естественной остановки, перерыв пропорционален работе
+const (
+	ModeClassic  = "classic"
+	ModeFlowtime = "flowtime"
+)
+
 // Состояния интервалов
 const (
 	StateNotStarted = iota
@@ -37,6 +48,36 @@ type Interval struct {
 	ActualDuration  time.Duration
 	Category        string
 	State           int
+	// Перерыв или работа - берётся из типа интервала
+	Break bool
+	// Профиль, по которому интервал запланирован
+	Profile string
+	// Задача и метки - для отчётов и выгрузки
+	Task string
+	Tags []string
+	// Задача из списка задач, если интервал к ней привязан; 0 - не привязан
+	TaskID int64
+	// Прерывания во время интервала
+	Interruptions []Interruption
+	// Паузы: когда ставили на паузу и когда продолжили
+	Pauses []PauseSegment
+	// Версия записи - растёт с каждым обновлением в репозитории.
+	// По ней репозиторий замечает, что интервал изменили после чтения.
+	Version int64
+}
+
+// Время переработки - сколько интервал исполнялся сверх запланированного
+func (i Interval) Overtime() time.Duration {
+	if i.OpenEnded() || i.ActualDuration <= i.PlannedDuration {
+		return 0
+	}
+	return i.ActualDuration - i.PlannedDuration
+}
+
+// Открытый интервал (секундомер) - без запланированной продолжительности,
+// завершается только явно через Finish
+func (i Interval) OpenEnded() bool {
+	return i.PlannedDuration == 0
 }
 
 // Репозиторий интервалов
@@ -44,7 +85,9 @@ type Repository interface {
 	// Создаёт новый интервал в репозитории
 	Create(i Interval) (int64, error)
 
-	// Обновить интервал в репозитории
+	// Обновить интервал в репозитории. Обновление условное: если версия
+	// в репозитории уже не та, что у i, - интервал изменили после чтения,
+	// и возвращается ErrConflict. При записи версия увеличивается на единицу.
 	Update(i Interval) error
 
 	// Возвращает интервал из репозитория по id
@@ -53,8 +96,35 @@ type Repository interface {
 	// Возвращает последний (текущий) интервал из репозитория
 	Last() (Interval, error)
 
-	// Возвращает n последних интервалов типа "перерыв" из репозитория
-	Breaks(n int) ([]Interval, error)
+	// Возвращает n последних интервалов из репозитория, самый свежий - первый
+	Recent(n int) ([]Interval, error)
+
+	// Возвращает интервалы, начатые в промежутке [from, to)
+	Range(from, to time.Time) ([]Interval, error)
+}
+
+// Репозиторий, который умеет создать много интервалов одной записью.
+// Необязательный: без него интервалы создаются по одному (см. CreateMany).
+type BulkCreator interface {
+	// Создаёт интервалы в репозитории, возвращает их ID по порядку
+	CreateMany(intervals []Interval) ([]int64, error)
+}
+
+// Создаёт интервалы в репозитории - одной записью, если репозиторий
+// это умеет. Возвращает ID созданных интервалов по порядку.
+func CreateMany(repo Repository, intervals []Interval) ([]int64, error) {
+	if bulk, ok := repo.(BulkCreator); ok {
+		return bulk.CreateMany(intervals)
+	}
+	ids := make([]int64, 0, len(intervals))
+	for _, i := range intervals {
+		id, err := repo.Create(i)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
 
 // Ошибки
@@ -64,6 +134,14 @@ var (
 	ErrIntervalCompleted  = errors.New("интервал завершен или отменён")
 	ErrInvalidState       = errors.New("неверное состояние интервала")
 	ErrInvalidID          = errors.New("неверный индентификатор интервала")
+	ErrInvalidMode        = errors.New("неизвестный режим работы")
+	ErrInvalidRatios      = errors.New("неверная таблица коэффициентов Flowtime")
+	ErrInvalidSchedule    = errors.New("неверное расписание интервалов")
+	ErrUnknownProfile     = errors.New("неизвестный профиль")
+	ErrInvalidProfile     = errors.New("неверный профиль")
+	ErrInvalidConfig      = errors.New("неверная конфигурация")
+	ErrIntervalActive     = errors.New("интервал исполняется или на паузе")
+	ErrConflict           = errors.New("интервал изменён после чтения")
 )
 
 // Конфигурация для создания нового интервала
@@ -75,6 +153,36 @@ type IntevalConfig struct {
 	PomodoroDuration   time.Duration
 	ShortBreakDuration time.Duration
 	LongBreakDuration  time.Duration
+	// Режим переработки: по истечении PlannedDuration интервал не завершается,
+	// а продолжает считать время, пока его не завершат явно через Finish
+	Overtime bool
+	// Режим работы - ModeClassic или ModeFlowtime
+	Mode string
+	// Таблица коэффициентов для расчёта перерывов в режиме Flowtime
+	FlowtimeRatios []FlowtimeRatio
+	// Собственное расписание интервалов. Если последовательность не задана -
+	// используется классическое расписание из трёх продолжительностей выше.
+	Schedule Schedule
+	// Имя действующего профиля - настройки выше взяты из него
+	Profile string
+	// Задача и метки, которые получат новые интервалы.
+	// TaskID - задача из списка задач, 0 - задача задана только текстом.
+	Task   string
+	TaskID int64
+	Tags   []string
+	// Максимальная пауза (0 - без ограничения) и что делать с интервалом,
+	// простоявшим на паузе дольше: PauseActionCancel или PauseActionReset
+	MaxPause    time.Duration
+	PauseAction string
+
+	// Зарегистрированные профили и мьютекс для их переключения на ходу.
+	// Мьютекс - указатель, чтобы конфиг можно было копировать.
+	profiles []Profile
+	mu       *sync.RWMutex
+	// Очередь записей интервалов
+	writes *writes
+	// Подписчики на события интервалов
+	events *subscribers
 }
 
 // Контруктор IntevalConfig
@@ -84,6 +192,12 @@ func NewConfig(repo Repository, pomodoro, shortBreak, longBreak time.Duration) *
 		PomodoroDuration:   25 * time.Minute,
 		ShortBreakDuration: 5 * time.Minute,
 		LongBreakDuration:  15 * time.Minute,
+		Mode:               ModeClassic,
+		FlowtimeRatios:     DefaultFlowtimeRatios(),
+		Profile:            DefaultProfile,
+		mu:                 &sync.RWMutex{},
+		writes:             &writes{progress: map[int64]progress{}},
+		events:             &subscribers{subs: map[int]Subscriber{}},
 	}
 
 	if pomodoro > 0 {
@@ -99,45 +213,9 @@ func NewConfig(repo Repository, pomodoro, shortBreak, longBreak time.Duration) *
 	return c
 }
 
-// Возвращает следующую категорию для репозитория r
-func nextCategory(r Repository) (string, error) {
-	li, err := r.Last()
-	// Интервалов ещё не было - нужно начинать работать (pomodoro)
-	if err != nil && err == ErrNoIntervals {
-		return CategoryPomodoro, nil
-	}
-	if err != nil {
-		return "", err
-	}
-
-	// После перерыва возвращаемся к работе - pomodoro
-	if li.Category == CategoryShortBreak || li.Category == CategoryLongBreak {
-		return CategoryPomodoro, nil
-	}
-
-	// Если мы оказались здесь - то мы работаем сейчас, и следующий интервал - перерыв.
-	// И нам нужно выяснить, какой тип перерыва будет следующим.
-
-	// Возьмём 3 последних перерыва в слайс lastBreaks
-	lastBreaks, err := r.Breaks(3)
-	if err != nil {
-		return "", err
-	}
-
-	// Если перерывов в целом было менее 3 - то следующий перерыв будет короткий
-	if len(lastBreaks) < 3 {
-		return CategoryShortBreak, nil
-	}
-
-	// Если среди 3-х последних перерывов был длинный - то следующий перерыв будет короткий
-	for _, i := range lastBreaks {
-		if i.Category == CategoryLongBreak {
-			return CategoryShortBreak, nil
-		}
-	}
-
-	// Все 3 последних перерыва были короткими - следующий будет длинный
-	return CategoryLongBreak, nil
+// Репозиторий конфига - для чтения истории за пределами пакета
+func (c *IntevalConfig) Repository() Repository {
+	return c.repo
 }
 
 type Callback func(Interval)
@@ -160,7 +238,30 @@ func tick(ctx context.Context, id int64, config *IntevalConfig, start, periodic,
 	// мы вычисляем время истечения с учетом возможного рестарта, когда в
 	// ActualDuration уже накоплено какое-то количесто секунд.
 	// В канал expire получаем событие после оставшегося врмемени на выполнение.
-	expire := time.After(i.PlannedDuration - i.ActualDuration)
+	// У открытого интервала (секундомера) времени истечения нет -
+	// nil-канал в select не срабатывает никогда.
+	var expire <-chan time.Time
+	if !i.OpenEnded() {
+		expire = time.After(i.PlannedDuration - i.ActualDuration)
+	}
+	// По StartTime замечаем, что интервал начали заново (Reset) -
+	// тогда таймер истечения нужно перезапустить
+	started := i.StartTime
+	restarted := func(i Interval) bool {
+		if i.StartTime.Equal(started) {
+			return false
+		}
+		started = i.StartTime
+		expire = nil
+		if !i.OpenEnded() {
+			// Считаем от момента сброса - его могли сделать почти секунду назад
+			expire = time.After(time.Until(i.StartTime.Add(i.PlannedDuration - i.ActualDuration)))
+		}
+		return true
+	}
+	// Режим переработки фиксируем на старте - чтобы исполняющийся
+	// интервал не зависел от изменений конфига
+	overtime := config.current().Overtime
 	start(i)
 
 	for {
@@ -169,64 +270,99 @@ func tick(ctx context.Context, id int64, config *IntevalConfig, start, periodic,
 		case <-ticker.C: // из канала ticker
 			// сюда попадаем каждую секунду
 
-			// Получаем интервал из репозитория
-			i, err := config.repo.ByID(id)
-			if err != nil {
-				return err
-			}
-
-			// если интервал в состоянии StatePaused - не делаем ничего
-			if i.State == StatePaused {
-				return nil
-			}
-
 			// Увеличиваем продолжительность ActualDuration
 			// на одну секунду (потому что мы здесь оказываемся каждую секунду)
-			// Обновляем интервал в репозитории
-			// Вызываем callback periodic
-			i.ActualDuration += time.Second
-			if err := config.repo.Update(i); err != nil {
+			// на свежей копии из репозитория и записываем её обратно
+			i, err := config.update(id, func(i *Interval) error {
+				// Интервал поставили на паузу, завершили или отменили
+				// извне (TUI, API) - тикать больше нечего
+				if i.State != StateRunning {
+					return errUnchanged
+				}
+				restarted(*i)
+				i.ActualDuration += time.Second
+				// В репозиторий ход пишем раз в progressEvery
+				if i.ActualDuration%progressEvery != 0 {
+					return errDeferred
+				}
+				return nil
+			})
+			if errors.Is(err, errUnchanged) {
+				return stopped(i, end)
+			}
+			if err != nil {
 				return err
 			}
+			// Вызываем callback periodic
 			periodic(i)
+			config.emit(EventTick, i)
 		case <-expire: // из канала expire
 			// Таймер expire закончился
-			i, err := config.repo.ByID(id)
+			// В режиме переработки интервал не завершаем - продолжаем
+			// считать время до явного Finish. Nil-канал в select
+			// не срабатывает никогда, так что expire больше не сработает.
+			if overtime {
+				expire = nil
+				continue
+			}
+			// Сначала записываем в репозиторий - чтобы в end() репозиторий
+			// уже знал о завершении интервала (например, для отчёта)
+			i, err := config.update(id, func(i *Interval) error {
+				// Между тиками интервал могли остановить извне
+				// или начать заново - тогда ждём нового истечения
+				if i.State != StateRunning || restarted(*i) {
+					return errUnchanged
+				}
+				i.State = StateDone
+				return nil
+			})
+			if errors.Is(err, errUnchanged) {
+				if i.State != StateRunning {
+					return stopped(i, end)
+				}
+				continue
+			}
 			if err != nil {
 				return err
 			}
-			i.State = StateDone
+			config.emit(EventDone, i)
 			end(i)
-			return config.repo.Update(i)
+			return nil
 		case <-ctx.Done():
 			// Получили сигнал из контекста - нужно прервать исполнение
-			i, err := config.repo.ByID(id)
+			i, err := config.update(id, func(i *Interval) error {
+				if i.State != StateRunning {
+					return errUnchanged
+				}
+				i.State = StateCancelled
+				return nil
+			})
+			if errors.Is(err, errUnchanged) {
+				return nil
+			}
 			if err != nil {
 				return err
 			}
-			i.State = StateCancelled
-			return config.repo.Update(i)
+			config.emit(EventCancel, i)
+			return nil
 		}
 	}
 }
 
-func newInterval(config *IntevalConfig) (Interval, error) {
-	i := Interval{}
+// Интервал остановлен извне: для завершённого вызываем end,
+// на паузе и отменённый - просто выходим
+func stopped(i Interval, end Callback) error {
+	if i.State == StateDone {
+		end(i)
+	}
+	return nil
+}
 
-	category, err := nextCategory(config.repo)
+func newInterval(config *IntevalConfig) (Interval, error) {
+	i, err := planInterval(config)
 	if err != nil {
 		return i, err
 	}
-	i.Category = category
-
-	switch category {
-	case CategoryPomodoro:
-		i.PlannedDuration = config.PomodoroDuration
-	case CategoryShortBreak:
-		i.PlannedDuration = config.ShortBreakDuration
-	case CategoryLongBreak:
-		i.PlannedDuration = config.LongBreakDuration
-	}
 
 	// Записываем инетрвал в репозиторий
 	// и получаем его ID из репозитория
@@ -236,10 +372,34 @@ func newInterval(config *IntevalConfig) (Interval, error) {
 	return i, nil
 }
 
+// Следующий интервал по расписанию - без записи в репозиторий
+func planInterval(config *IntevalConfig) (Interval, error) {
+	// Настройки берём снимком - профиль могут переключить в любой момент
+	p := config.current()
+
+	recent, err := config.repo.Recent(p.history())
+	if err != nil {
+		return Interval{}, err
+	}
+	i := p.plan(recent)
+	config.mu.RLock()
+	i.Task = config.Task
+	i.TaskID = config.TaskID
+	i.Tags = config.Tags
+	config.mu.RUnlock()
+	return i, nil
+}
+
 func GetInterval(config *IntevalConfig) (Interval, error) {
 	i := Interval{} // TODO: здесь предупреждение компилятора - как убрать
 	var err error
 
+	// Пауза последнего интервала могла истечь, пока его никто не проверял -
+	// тогда он уже отменён или начат заново
+	if _, _, err = ExpirePause(config); err != nil {
+		return i, err
+	}
+
 	// Получаем последний интервал из репозитория
 	i, err = config.repo.Last()
 
@@ -261,40 +421,199 @@ func GetInterval(config *IntevalConfig) (Interval, error) {
 	return newInterval(config)
 }
 
+// Текущий интервал для показа - ничего не записывает в репозиторий.
+// Исполняющийся или приостановленный возвращается вместе с ходом, ещё
+// не записанным в репозиторий. Если такого нет - следующий по расписанию,
+// но не созданный: ID у него нулевой. GetInterval его бы создал.
+func Current(config *IntevalConfig) (Interval, error) {
+	i, err := config.repo.Last()
+	if err != nil && !errors.Is(err, ErrNoIntervals) {
+		return i, err
+	}
+	if err != nil || i.State == StateCancelled || i.State == StateDone {
+		return planInterval(config)
+	}
+
+	config.writes.Lock()
+	defer config.writes.Unlock()
+	config.writes.merge(&i)
+	return i, nil
+}
+
 // Запустить интервал
 func (i Interval) Start(ctx context.Context, config *IntevalConfig,
 	start, periodic, end Callback,
 ) error {
-	switch i.State {
-	case StateRunning:
-		// Уже исполняется - не делаем ничего
-		return nil
-	case StateNotStarted:
-		// Нужно запустить - интервал не стартован
-		i.StartTime = time.Now()
-		fallthrough // следующий case будет исполнен принудительно - стартуем интервал
-	case StatePaused:
-		// Мы на паузе (или ещё на стартовали) - возобновим и запишем в репозиторий
-		i.State = StateRunning
-		if err := config.repo.Update(i); err != nil {
+	// Продолжить можно, только пока пауза не истекла. Истекла - интервал
+	// отменяется (и запустить его уже нельзя) или запускается заново.
+	if i.State == StatePaused {
+		if _, _, err := ExpirePause(config); err != nil {
 			return err
 		}
-		return tick(ctx, i.ID, config, start, periodic, end)
-	case StateCancelled, StateDone:
-		return fmt.Errorf("%w: нелзя запустить завершенный интервал", ErrIntervalCompleted)
-	default:
-		return fmt.Errorf("%w: %d", ErrInvalidState, i.State)
 	}
+
+	// Состояние проверяем на свежей копии: запустить интервал могли
+	// одновременно из TUI и API - тикать тогда должен только один из них
+	var event string
+	i, err := config.update(i.ID, func(i *Interval) error {
+		switch i.State {
+		case StateRunning:
+			// Уже исполняется - не делаем ничего
+			return errUnchanged
+		case StateNotStarted:
+			// Нужно запустить - интервал не стартован
+			event = EventStart
+			i.StartTime = time.Now()
+		case StatePaused:
+			// Мы на паузе - возобновим
+			event = EventResume
+		case StateCancelled, StateDone:
+			return fmt.Errorf("%w: нелзя запустить завершенный интервал", ErrIntervalCompleted)
+		default:
+			return fmt.Errorf("%w: %d", ErrInvalidState, i.State)
+		}
+		i.endPause(time.Now())
+		i.State = StateRunning
+		return nil
+	})
+	if errors.Is(err, errUnchanged) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	config.emit(event, i)
+	return tick(ctx, i.ID, config, start, periodic, end)
+}
+
+// Подхватить исполняющийся интервал - продолжить его отсчёт в этом процессе.
+// Start исполняющийся интервал не трогает: считается, что его уже отсчитывают.
+// Follow нужен, когда прежний процесс вышел, оставив интервал идти, -
+// например, фоновому pomo serve после выхода из TUI.
+func (i Interval) Follow(ctx context.Context, config *IntevalConfig,
+	start, periodic, end Callback,
+) error {
+	if i.State != StateRunning {
+		return ErrIntervalNotRunning
+	}
+	return tick(ctx, i.ID, config, start, periodic, end)
 }
 
 // Поставить интервал на паузу
 func (i Interval) Pause(config *IntevalConfig) error {
-	// Нельзя поставить на паузу интервал, который не исполняется
-	if i.State != StateRunning {
+	i, err := config.update(i.ID, func(i *Interval) error {
+		// Нельзя поставить на паузу интервал, который не исполняется
+		if i.State != StateRunning {
+			return ErrIntervalNotRunning
+		}
+		// Установим состояние в паузу и начнём отрезок паузы
+		i.State = StatePaused
+		i.beginPause(time.Now())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	config.emit(EventPause, i)
+	return nil
+}
+
+// Завершить интервал явно - досрочно или после переработки
+func (i Interval) Finish(config *IntevalConfig) error {
+	i, err := config.update(i.ID, func(i *Interval) error {
+		if err := i.stoppable(); err != nil {
+			return err
+		}
+		// Исполняющийся интервал завершит tick() на следующем тике,
+		// приостановленный - просто записываем в репозиторий
+		i.State = StateDone
+		i.endPause(time.Now())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	config.emit(EventDone, i)
+	return nil
+}
+
+// Отменить исполняющийся или приостановленный интервал.
+// Отменённый интервал засчитывается в расписании - следующим будет
+// запланирован интервал после него.
+func (i Interval) Cancel(config *IntevalConfig) error {
+	i, err := config.update(i.ID, func(i *Interval) error {
+		if err := i.stoppable(); err != nil {
+			return err
+		}
+		// Исполняющийся интервал tick() остановит на следующем тике
+		i.State = StateCancelled
+		i.endPause(time.Now())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	config.emit(EventCancel, i)
+	return nil
+}
+
+// Начать текущий интервал заново - с полной продолжительностью.
+// Исполняющийся продолжает идти с нуля: tick() заметит новый StartTime
+// и перезапустит таймер истечения. Приостановленный становится неначатым.
+func (i Interval) Reset(config *IntevalConfig) error {
+	i, err := config.update(i.ID, func(i *Interval) error {
+		if err := i.stoppable(); err != nil {
+			return err
+		}
+		i.reset(time.Now())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	config.emit(EventReset, i)
+	return nil
+}
+
+// Можно ли завершить, отменить или начать заново интервал в его состоянии -
+// только исполняющийся или приостановленный
+func (i Interval) stoppable() error {
+	switch i.State {
+	case StateRunning, StatePaused:
+		return nil
+	case StateCancelled, StateDone:
+		return fmt.Errorf("%w: интервал уже завершен", ErrIntervalCompleted)
+	default:
 		return ErrIntervalNotRunning
 	}
+}
+
+// Пропустить текущий интервал - в любом незавершённом состоянии, в том числе
+// ещё не начатый - и запланировать следующий по расписанию.
+// Возвращает следующий интервал, он не запускается.
+func Skip(config *IntevalConfig) (Interval, error) {
+	i, err := GetInterval(config)
+	if err != nil {
+		return i, err
+	}
 
-	// Установим состояние в паузу и обновим интервал в репозитории
-	i.State = StatePaused
-	return config.repo.Update(i)
+	// Не начатый интервал отменяем без события - для подписчиков его не было.
+	// Время начала - момент пропуска: с нулевым временем он остался бы
+	// последним в истории вперёд следующего
+	_, err = config.update(i.ID, func(i *Interval) error {
+		if i.State != StateNotStarted {
+			return errUnchanged
+		}
+		i.State = StateCancelled
+		i.StartTime = time.Now()
+		return nil
+	})
+	if errors.Is(err, errUnchanged) {
+		// Интервал уже начат - отменяем как обычно
+		err = i.Cancel(config)
+	}
+	if err != nil {
+		return i, err
+	}
+	return GetInterval(config)
 }
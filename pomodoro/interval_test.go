@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -33,12 +36,17 @@ func TestNewConfig(t *testing.T) {
 			if config.PomodoroDuration != tc.expect.PomodoroDuration ||
 				config.LongBreakDuration != tc.expect.LongBreakDuration ||
 				config.ShortBreakDuration != tc.expect.ShortBreakDuration {
-				t.Errorf("\nОжидали конфиг: %q,\nполучили: %q", tc.expect, *config)
+				t.Errorf("\nОжидали конфиг: %q,\nполучили: %q", durations(tc.expect), durations(*config))
 			}
 		})
 	}
 }
 
+// Продолжительности интервалов конфига - их и сравнивает TestNewConfig
+func durations(c pomodoro.IntevalConfig) []time.Duration {
+	return []time.Duration{c.PomodoroDuration, c.ShortBreakDuration, c.LongBreakDuration}
+}
+
 func TestGetInterval(t *testing.T) {
 	// Получаем repo из helper-функции - это repo для теста
 	repo, cleanup := getRepo(t)
@@ -116,6 +124,33 @@ func TestGetInterval(t *testing.T) {
 	}
 }
 
+// Current только читает: следующий интервал показывает, но не создаёт
+func TestCurrent(t *testing.T) {
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, time.Minute, time.Minute, time.Minute)
+
+	i, err := pomodoro.Current(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.ID != 0 || i.State != pomodoro.StateNotStarted || i.Category != pomodoro.CategoryPomodoro {
+		t.Errorf("Ожидали не созданный Pomodoro, а получили: %+v", i)
+	}
+	if _, err := repo.Last(); !errors.Is(err, pomodoro.ErrNoIntervals) {
+		t.Errorf("Ожидали пустой репозиторий, а получили: %v", err)
+	}
+
+	created, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i, err = pomodoro.Current(config); err != nil || i.ID != created.ID {
+		t.Errorf("Ожидали интервал %d, а получили: %+v (%v)", created.ID, i, err)
+	}
+}
+
 func TestPause(t *testing.T) {
 	// Минимальная продолжительность интервала для этого теста - 2 секунды,
 	// потому что tick() стартует тикер, который срабатывает раз в секунду.
@@ -309,3 +344,639 @@ func TestStart(t *testing.T) {
 		})
 	}
 }
+
+func TestFinish(t *testing.T) {
+	const duration = 1 * time.Second
+
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, duration, duration, duration)
+	config.Overtime = true
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endCalled := false
+	start := func(pomodoro.Interval) {}
+	end := func(i pomodoro.Interval) {
+		endCalled = true
+		if i.State != pomodoro.StateDone {
+			t.Errorf("Ожидали состояние: %d, а получили: %d", pomodoro.StateDone, i.State)
+		}
+	}
+	periodic := func(i pomodoro.Interval) {
+		// В режиме переработки интервал сам не завершается -
+		// завершаем его явно через секунду после истечения
+		if i.ActualDuration == 2*duration {
+			if err := i.Finish(config); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := i.Start(context.Background(), config, start, periodic, end); err != nil {
+		t.Fatal(err)
+	}
+
+	if !endCalled {
+		t.Error("Ожидали вызов callback [end]")
+	}
+
+	i, err = repo.ByID(i.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if i.State != pomodoro.StateDone {
+		t.Errorf("Ожидали состояние интервала: %d, а получили: %d", pomodoro.StateDone, i.State)
+	}
+
+	if i.Overtime() != duration {
+		t.Errorf("Ожидали переработку: %q, а получили: %q", duration, i.Overtime())
+	}
+
+	// Повторно завершить интервал нельзя
+	if err := i.Finish(config); !errors.Is(err, pomodoro.ErrIntervalCompleted) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %q", pomodoro.ErrIntervalCompleted, err)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	const duration = 2 * time.Second
+
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, duration, duration, duration)
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Не начатый интервал отменить нельзя - его можно только пропустить
+	if err := i.Cancel(config); !errors.Is(err, pomodoro.ErrIntervalNotRunning) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrIntervalNotRunning, err)
+	}
+
+	start := func(pomodoro.Interval) {}
+	end := func(pomodoro.Interval) {
+		t.Error("Callback [end] не должен вызываться для отменённого интервала")
+	}
+	periodic := func(i pomodoro.Interval) {
+		if err := i.Cancel(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// tick() должен сам остановиться на следующем тике после отмены
+	if err := i.Start(context.Background(), config, start, periodic, end); err != nil {
+		t.Fatal(err)
+	}
+
+	i, err = repo.ByID(i.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.State != pomodoro.StateCancelled {
+		t.Errorf("Ожидали состояние интервала: %d, а получили: %d", pomodoro.StateCancelled, i.State)
+	}
+	if i.ActualDuration != duration/2 {
+		t.Errorf("Ожидали продолжительность: %q, а получили: %q", duration/2, i.ActualDuration)
+	}
+
+	if err := i.Cancel(config); !errors.Is(err, pomodoro.ErrIntervalCompleted) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrIntervalCompleted, err)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, time.Minute, time.Minute, time.Minute)
+
+	first, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Category != pomodoro.CategoryPomodoro {
+		t.Fatalf("Ожидали категорию: %q, а получили: %q", pomodoro.CategoryPomodoro, first.Category)
+	}
+
+	// Пропущенный интервал засчитывается - следующим идёт перерыв
+	next, err := pomodoro.Skip(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.ID == first.ID || next.Category != pomodoro.CategoryShortBreak {
+		t.Errorf("Ожидали новый интервал %q, а получили: %+v", pomodoro.CategoryShortBreak, next)
+	}
+
+	skipped, err := repo.ByID(first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped.State != pomodoro.StateCancelled {
+		t.Errorf("Ожидали состояние интервала: %d, а получили: %d", pomodoro.StateCancelled, skipped.State)
+	}
+
+	// Запущенный после пропуска интервал - последний в истории
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	noop := func(pomodoro.Interval) {}
+	go func() {
+		done <- next.Start(ctx, config, func(pomodoro.Interval) { close(started) }, noop, noop)
+	}()
+	<-started
+
+	last, err := repo.Last()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.ID != next.ID || last.State != pomodoro.StateRunning {
+		t.Errorf("Ожидали последним исполняющийся интервал %d, а получили: %+v", next.ID, last)
+	}
+	current, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.ID != next.ID {
+		t.Errorf("Ожидали текущим интервал %d, а получили: %d", next.ID, current.ID)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReset(t *testing.T) {
+	const duration = 2 * time.Second
+
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, duration, duration, duration)
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Неначатый интервал сбрасывать нечего
+	if err := i.Reset(config); !errors.Is(err, pomodoro.ErrIntervalNotRunning) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrIntervalNotRunning, err)
+	}
+
+	resets := 0
+	config.Subscribe(func(e pomodoro.Event) {
+		if e.Type == pomodoro.EventReset {
+			resets++
+		}
+	})
+
+	// Сбрасываем на первом тике - интервал должен отработать
+	// полную продолжительность уже после сброса
+	var resetAt time.Time
+	periodic := func(i pomodoro.Interval) {
+		if !resetAt.IsZero() {
+			return
+		}
+		resetAt = time.Now()
+		if err := i.Reset(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	noop := func(pomodoro.Interval) {}
+	if err := i.Start(context.Background(), config, noop, periodic, noop); err != nil {
+		t.Fatal(err)
+	}
+	elapsed := time.Since(resetAt)
+
+	if i, err = repo.ByID(i.ID); err != nil {
+		t.Fatal(err)
+	}
+	if i.State != pomodoro.StateDone {
+		t.Errorf("Ожидали состояние: %q, а получили: %q",
+			pomodoro.StateName(pomodoro.StateDone), pomodoro.StateName(i.State))
+	}
+	if elapsed < duration-100*time.Millisecond || elapsed > duration+time.Second {
+		t.Errorf("Ожидали после сброса около %q, а прошло: %q", duration, elapsed)
+	}
+	if resets != 1 {
+		t.Errorf("Ожидали событий reset: 1, а получили: %d", resets)
+	}
+
+	// Завершённый интервал не сбрасывается
+	if err := i.Reset(config); !errors.Is(err, pomodoro.ErrIntervalCompleted) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrIntervalCompleted, err)
+	}
+
+	// Приостановленный после сброса - снова не начат
+	if i, err = pomodoro.GetInterval(config); err != nil {
+		t.Fatal(err)
+	}
+	pause := func(i pomodoro.Interval) {
+		if err := i.Pause(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := i.Start(context.Background(), config, noop, pause, noop); err != nil {
+		t.Fatal(err)
+	}
+	if i, err = repo.ByID(i.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.Reset(config); err != nil {
+		t.Fatal(err)
+	}
+	if i, err = repo.ByID(i.ID); err != nil {
+		t.Fatal(err)
+	}
+	if i.State != pomodoro.StateNotStarted || i.ActualDuration != 0 || !i.StartTime.IsZero() {
+		t.Errorf("Ожидали неначатый интервал, а получили: %+v", i)
+	}
+}
+
+func TestSetTask(t *testing.T) {
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, time.Minute, time.Minute, time.Minute)
+
+	// Событие task - только когда задачу получил сам интервал
+	var tasks []string
+	config.Subscribe(func(e pomodoro.Event) {
+		if e.Type == pomodoro.EventTask {
+			tasks = append(tasks, e.Interval.Task)
+		}
+	})
+
+	// Интервалов ещё нет - задачу получит первый из них
+	if _, err := pomodoro.SetTask(config, "отчёт"); err != nil {
+		t.Fatal(err)
+	}
+	first, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Task != "отчёт" {
+		t.Errorf("Ожидали задачу: %q, а получили: %q", "отчёт", first.Task)
+	}
+
+	// Незавершённый интервал получает новую задачу сразу
+	if _, err := pomodoro.SetTask(config, "ревью"); err != nil {
+		t.Fatal(err)
+	}
+	if first, err = repo.ByID(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if first.Task != "ревью" || config.ActiveTask() != "ревью" {
+		t.Errorf("Ожидали задачу: %q, а получили: %q и %q", "ревью", first.Task, config.ActiveTask())
+	}
+
+	// Отменённый интервал не меняется, меняется следующий за ним
+	if _, err := pomodoro.Skip(config); err != nil {
+		t.Fatal(err)
+	}
+	next, err := pomodoro.SetTask(config, "почта")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.ID == first.ID || next.Task != "почта" {
+		t.Errorf("Ожидали следующий интервал с задачей %q, а получили: %+v", "почта", next)
+	}
+	if first, err = repo.ByID(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if first.Task != "ревью" {
+		t.Errorf("Ожидали у отменённого интервала задачу: %q, а получили: %q", "ревью", first.Task)
+	}
+	if !slices.Equal(tasks, []string{"ревью", "почта"}) {
+		t.Errorf("Ожидали события task с задачами ревью и почта, а получили: %v", tasks)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	const duration = time.Second
+
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, duration, duration, duration)
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noop := func(pomodoro.Interval) {}
+	// Не начатый интервал подхватывать нечего
+	if err := i.Follow(context.Background(), config, noop, noop, noop); !errors.Is(err, pomodoro.ErrIntervalNotRunning) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrIntervalNotRunning, err)
+	}
+
+	// Интервал оставлен идти другим процессом - в репозитории он исполняется
+	i.State = pomodoro.StateRunning
+	i.StartTime = time.Now()
+	if err := repo.Update(i); err != nil {
+		t.Fatal(err)
+	}
+
+	ended := false
+	if err := i.Follow(context.Background(), config, noop, noop,
+		func(pomodoro.Interval) { ended = true }); err != nil {
+		t.Fatal(err)
+	}
+	if i, err = repo.ByID(i.ID); err != nil {
+		t.Fatal(err)
+	}
+	if i.State != pomodoro.StateDone || !ended {
+		t.Errorf("Ожидали завершённый интервал, а получили: %q, end вызван: %t",
+			pomodoro.StateName(i.State), ended)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	const duration = 2 * time.Second
+
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, duration, duration, duration)
+
+	// Тики считаем отдельно: после продолжения тик и истечение интервала
+	// приходятся на одну секунду, их порядок не определён
+	var events []string
+	ticks := 0
+	unsubscribe := config.Subscribe(func(e pomodoro.Event) {
+		if e.Type == pomodoro.EventTick {
+			ticks++
+			return
+		}
+		events = append(events, e.Type)
+	})
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emptyF := func(pomodoro.Interval) {}
+	pause := func(i pomodoro.Interval) {
+		if err := i.Pause(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := i.Start(context.Background(), config, emptyF, pause, emptyF); err != nil {
+		t.Fatal(err)
+	}
+
+	// Продолжаем после паузы и доводим до конца
+	if i, err = repo.ByID(i.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.Start(context.Background(), config, emptyF, emptyF, emptyF); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{
+		pomodoro.EventStart, pomodoro.EventPause, pomodoro.EventResume, pomodoro.EventDone,
+	}
+	if fmt.Sprint(events) != fmt.Sprint(expect) {
+		t.Errorf("Ожидали события: %v, а получили: %v", expect, events)
+	}
+	if ticks == 0 {
+		t.Error("Ожидали события тиков")
+	}
+
+	// После отписки события не приходят
+	unsubscribe()
+	events = nil
+	if _, err := pomodoro.Skip(config); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Errorf("Не ожидали событий после отписки, а получили: %v", events)
+	}
+}
+
+func TestPauseHistory(t *testing.T) {
+	const (
+		duration = 2 * time.Second
+		pause    = 300 * time.Millisecond
+	)
+
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, duration, duration, duration)
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emptyF := func(pomodoro.Interval) {}
+	pauseF := func(i pomodoro.Interval) {
+		if err := i.Pause(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := i.Start(context.Background(), config, emptyF, pauseF, emptyF); err != nil {
+		t.Fatal(err)
+	}
+
+	// На паузе отрезок открыт, время паузы идёт
+	if i, err = repo.ByID(i.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(i.Pauses) != 1 || !i.Pauses[0].End.IsZero() {
+		t.Fatalf("Ожидали одну продолжающуюся паузу, а получили: %+v", i.Pauses)
+	}
+	time.Sleep(pause)
+
+	// Продолжаем и доводим до конца - пауза закрывается
+	if err := i.Start(context.Background(), config, emptyF, emptyF, emptyF); err != nil {
+		t.Fatal(err)
+	}
+	if i, err = repo.ByID(i.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(i.Pauses) != 1 || i.Pauses[0].End.IsZero() {
+		t.Fatalf("Ожидали одну закрытую паузу, а получили: %+v", i.Pauses)
+	}
+	// Start на паузе возвращается только на следующем тике - отсюда запас в секунду
+	if paused := i.PausedTime(); paused < pause || paused > pause+2*time.Second {
+		t.Errorf("Ожидали паузу около %q, а получили: %q", pause, paused)
+	}
+	if i.WallClock() != i.ActualDuration+i.PausedTime() {
+		t.Errorf("Ожидали время по часам: %q, а получили: %q",
+			i.ActualDuration+i.PausedTime(), i.WallClock())
+	}
+
+	// Закрытая пауза со временем не растёт
+	paused := i.PausedTime()
+	time.Sleep(10 * time.Millisecond)
+	if i.PausedTime() != paused {
+		t.Errorf("Закрытая пауза не должна расти: %q -> %q", paused, i.PausedTime())
+	}
+
+	r := pomodoro.NewReport([]pomodoro.Interval{i})
+	if r.Work.Pauses != 1 || r.Work.Paused != paused {
+		t.Errorf("Ожидали в отчёте 1 паузу на %q, а получили: %d на %q",
+			paused, r.Work.Pauses, r.Work.Paused)
+	}
+}
+
+// Пауза, сброс и прерывания приходят из TUI и API в любой момент - в том числе
+// между чтением и записью интервала в tick. Ни одно изменение не должно
+// затереться тиком. Имеет смысл запускать с -race.
+func TestConcurrentUpdates(t *testing.T) {
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, time.Hour, time.Minute, time.Minute)
+
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	config.Subscribe(func(e pomodoro.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e.Type)
+	})
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	noop := func(pomodoro.Interval) {}
+	go func() {
+		done <- i.Start(context.Background(), config, func(pomodoro.Interval) { close(started) }, noop, noop)
+	}()
+	<-started
+
+	// Прерывания из нескольких горутин сразу, пока идут тики.
+	// Возвращает, сколько прерываний записано.
+	interrupt := func(d time.Duration) int {
+		var (
+			n  atomic.Int64
+			wg sync.WaitGroup
+		)
+		deadline := time.Now().Add(d)
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for time.Now().Before(deadline) {
+					if _, err := pomodoro.Interrupt(config, pomodoro.InterruptionInternal, ""); err != nil {
+						t.Error(err)
+						return
+					}
+					n.Add(1)
+					time.Sleep(5 * time.Millisecond)
+				}
+			}()
+		}
+		wg.Wait()
+		return int(n.Load())
+	}
+
+	interrupt(1500 * time.Millisecond)
+	if err := i.Reset(config); err != nil {
+		t.Fatal(err)
+	}
+	// Прерывания до сброса стёрты, после - должны сохраниться все
+	expInterruptions := interrupt(1500 * time.Millisecond)
+	if err := i.Pause(config); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("tick не остановился после паузы")
+	}
+
+	i, err = repo.ByID(i.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.State != pomodoro.StatePaused {
+		t.Errorf("Ожидали состояние интервала: %d, а получили: %d", pomodoro.StatePaused, i.State)
+	}
+	if len(i.Interruptions) != expInterruptions {
+		t.Errorf("Ожидали прерываний: %d, а получили: %d", expInterruptions, len(i.Interruptions))
+	}
+	// После сброса прошло полторы секунды - тиков было один-два.
+	// Больше - значит, тик затёр сброс.
+	if i.ActualDuration < time.Second || i.ActualDuration > 2*time.Second {
+		t.Errorf("Ожидали продолжительность от 1s до 2s, а получили: %s", i.ActualDuration)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if k := slices.Index(events, pomodoro.EventPause); k < 0 || slices.Contains(events[k:], pomodoro.EventTick) {
+		t.Errorf("Ожидали паузу без тиков после неё, а получили: %v", events)
+	}
+}
+
+// Запуск одновременно из TUI и API: тикать должен только один из них,
+// иначе время считалось бы дважды
+func TestConcurrentStart(t *testing.T) {
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, time.Hour, time.Minute, time.Minute)
+
+	var starts atomic.Int64
+	config.Subscribe(func(e pomodoro.Event) {
+		if e.Type == pomodoro.EventStart {
+			starts.Add(1)
+		}
+	})
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+
+	noop := func(pomodoro.Interval) {}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := i.Start(ctx, config, noop, noop, noop); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := starts.Load(); n != 1 {
+		t.Errorf("Ожидали один запуск, а получили: %d", n)
+	}
+	i, err = repo.ByID(i.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.ActualDuration > 2*time.Second {
+		t.Errorf("Ожидали не больше 2s за 2.5s, а получили: %s", i.ActualDuration)
+	}
+}
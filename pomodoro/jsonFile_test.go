@@ -0,0 +1,104 @@
+//go:build !inmemory
+
+package pomodoro_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/repository"
+)
+
+// Helper function - возвращает репозиторий для тестов + cleanup-function
+func getRepo(t *testing.T) (pomodoro.Repository, func()) {
+	t.Helper()
+
+	// Файл создаётся во временном каталоге теста - его удалит сам testing,
+	// поэтому cleanup function пустая
+	repo, err := repository.NewJSONFileRepo(filepath.Join(t.TempDir(), "pomo.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, func() {}
+}
+
+// Один файл на несколько процессов: TUI, pomo serve, команды pomo.
+// Каждый процесс - свой экземпляр репозитория.
+func TestJSONFileShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pomo.json")
+	open := func() pomodoro.Repository {
+		repo, err := repository.NewJSONFileRepo(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	}
+	r1, r2 := open(), open()
+
+	id, err := r1.Create(pomodoro.Interval{Category: pomodoro.CategoryPomodoro, State: pomodoro.StateRunning})
+	if err != nil {
+		t.Fatal(err)
+	}
+	i1, err := r1.ByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Пауза из соседнего терминала: размер файла тот же, меняется одна цифра
+	i2, err := r2.ByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i2.State = pomodoro.StatePaused
+	if err := r2.Update(i2); err != nil {
+		t.Fatal(err)
+	}
+	if i, err := r1.ByID(id); err != nil || i.State != pomodoro.StatePaused {
+		t.Errorf("Ожидали состояние интервала: %d, а получили: %d (%v)", pomodoro.StatePaused, i.State, err)
+	}
+
+	// Запись по копии, прочитанной до паузы, паузу бы затёрла
+	i1.ActualDuration = time.Second
+	if err := r1.Update(i1); !errors.Is(err, pomodoro.ErrConflict) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrConflict, err)
+	}
+
+	// Одновременные записи из разных процессов не теряются
+	const writers, each = 4, 20
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repo := open()
+			for range each {
+				if _, err := repo.Create(pomodoro.Interval{Category: pomodoro.CategoryShortBreak}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	all, err := r1.Recent(writers*each + 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != writers*each+1 {
+		t.Errorf("Ожидали интервалов: %d, а получили: %d", writers*each+1, len(all))
+	}
+
+	// Правка вручную - на месте, без rename
+	if err := os.WriteFile(path, []byte(`{"version":2,"intervals":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r1.Last(); !errors.Is(err, pomodoro.ErrNoIntervals) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrNoIntervals, err)
+	}
+}
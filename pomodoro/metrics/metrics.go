@@ -0,0 +1,187 @@
+// Метрики интервалов в текстовом формате Prometheus.
+//
+// Коллектор подписывается на события интервалов конфига, поэтому считает
+// всё, что происходит с интервалами, - неважно, из TUI, API или по таймеру.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Границы корзин гистограммы продолжительности Pomodoro, в секундах
+var DurationBuckets = []float64{
+	5 * 60, 10 * 60, 15 * 60, 20 * 60, 25 * 60, 30 * 60, 45 * 60, 60 * 60, 90 * 60,
+}
+
+// Гистограмма по одной категории
+type histogram struct {
+	counts []uint64 // по корзинам DurationBuckets, не накопительно
+	count  uint64
+	sum    float64
+}
+
+// Коллектор метрик
+type Collector struct {
+	mu        sync.Mutex
+	started   map[string]uint64
+	completed map[string]uint64
+	cancelled map[string]uint64
+	durations map[string]*histogram
+	state     int
+	remaining time.Duration
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		started:   map[string]uint64{},
+		completed: map[string]uint64{},
+		cancelled: map[string]uint64{},
+		durations: map[string]*histogram{},
+		state:     pomodoro.StateNotStarted,
+	}
+}
+
+// Подписчик на события интервалов
+func (c *Collector) Observe(e pomodoro.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := e.Interval
+	switch e.Type {
+	case pomodoro.EventStart:
+		c.started[i.Category]++
+	case pomodoro.EventDone:
+		c.completed[i.Category]++
+		// Гистограмма - только по рабочим интервалам
+		if !i.Break {
+			c.observeDuration(i.Category, i.ActualDuration)
+		}
+	case pomodoro.EventCancel:
+		c.cancelled[i.Category]++
+	}
+
+	c.state = i.State
+	c.remaining = 0
+	if (i.State == pomodoro.StateRunning || i.State == pomodoro.StatePaused) &&
+		!i.OpenEnded() && i.ActualDuration < i.PlannedDuration {
+		c.remaining = i.PlannedDuration - i.ActualDuration
+	}
+}
+
+func (c *Collector) observeDuration(category string, d time.Duration) {
+	h, ok := c.durations[category]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(DurationBuckets))}
+		c.durations[category] = h
+	}
+
+	s := d.Seconds()
+	h.count++
+	h.sum += s
+	for k, le := range DurationBuckets {
+		if s <= le {
+			h.counts[k]++
+			break
+		}
+	}
+}
+
+// Пишет метрики в текстовом формате Prometheus
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+
+	counter := func(name, help string, values map[string]uint64) {
+		header(cw, name, "counter", help)
+		for _, cat := range slices.Sorted(maps.Keys(values)) {
+			fmt.Fprintf(cw, "%s{category=%s} %d\n", name, quote(cat), values[cat])
+		}
+	}
+	counter("pomo_intervals_started_total", "Запущено интервалов.", c.started)
+	counter("pomo_intervals_completed_total", "Завершено интервалов.", c.completed)
+	counter("pomo_intervals_cancelled_total", "Отменено интервалов.", c.cancelled)
+
+	header(cw, "pomo_interval_state", "gauge", "Состояние текущего интервала: 1 у действующего.")
+	for _, s := range []int{
+		pomodoro.StateNotStarted, pomodoro.StateRunning, pomodoro.StatePaused,
+		pomodoro.StateDone, pomodoro.StateCancelled,
+	} {
+		v := 0
+		if s == c.state {
+			v = 1
+		}
+		fmt.Fprintf(cw, "pomo_interval_state{state=%s} %d\n", quote(pomodoro.StateName(s)), v)
+	}
+
+	header(cw, "pomo_interval_remaining_seconds", "gauge", "Осталось секунд до истечения текущего интервала.")
+	fmt.Fprintf(cw, "pomo_interval_remaining_seconds %s\n", number(c.remaining.Seconds()))
+
+	const hname = "pomo_pomodoro_duration_seconds"
+	header(cw, hname, "histogram", "Фактическая продолжительность завершённых рабочих интервалов.")
+	for _, cat := range slices.Sorted(maps.Keys(c.durations)) {
+		h := c.durations[cat]
+		var cumulative uint64
+		for k, le := range DurationBuckets {
+			cumulative += h.counts[k]
+			fmt.Fprintf(cw, "%s_bucket{category=%s,le=%s} %d\n", hname, quote(cat), quote(number(le)), cumulative)
+		}
+		fmt.Fprintf(cw, "%s_bucket{category=%s,le=\"+Inf\"} %d\n", hname, quote(cat), h.count)
+		fmt.Fprintf(cw, "%s_sum{category=%s} %s\n", hname, quote(cat), number(h.sum))
+		fmt.Fprintf(cw, "%s_count{category=%s} %d\n", hname, quote(cat), h.count)
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, bw.Flush()
+}
+
+// Отдаёт метрики по HTTP - для /metrics
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+func header(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// Значение метки в кавычках с экранированием по формату Prometheus
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func number(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Считает записанные байты и запоминает первую ошибку
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
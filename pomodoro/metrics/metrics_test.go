@@ -0,0 +1,73 @@
+package metrics_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/metrics"
+)
+
+func TestCollector(t *testing.T) {
+	c := metrics.NewCollector()
+
+	work := pomodoro.Interval{
+		Category:        pomodoro.CategoryPomodoro,
+		PlannedDuration: 25 * time.Minute,
+		State:           pomodoro.StateRunning,
+	}
+	brk := pomodoro.Interval{
+		Category:        pomodoro.CategoryShortBreak,
+		PlannedDuration: 5 * time.Minute,
+		Break:           true,
+		State:           pomodoro.StateRunning,
+	}
+	event := func(typ string, i pomodoro.Interval, state int, actual time.Duration) {
+		i.State = state
+		i.ActualDuration = actual
+		c.Observe(pomodoro.Event{Type: typ, Interval: i})
+	}
+
+	event(pomodoro.EventStart, work, pomodoro.StateRunning, 0)
+	event(pomodoro.EventDone, work, pomodoro.StateDone, 27*time.Minute)
+	event(pomodoro.EventStart, brk, pomodoro.StateRunning, 0)
+	event(pomodoro.EventDone, brk, pomodoro.StateDone, 5*time.Minute)
+	event(pomodoro.EventStart, work, pomodoro.StateRunning, 0)
+	event(pomodoro.EventCancel, work, pomodoro.StateCancelled, 3*time.Minute)
+	event(pomodoro.EventStart, work, pomodoro.StateRunning, 0)
+	event(pomodoro.EventTick, work, pomodoro.StateRunning, 10*time.Minute)
+
+	var out bytes.Buffer
+	if _, err := c.WriteTo(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, exp := range []string{
+		`# TYPE pomo_intervals_started_total counter`,
+		`pomo_intervals_started_total{category="Pomodoro"} 3`,
+		`pomo_intervals_started_total{category="ShortBreak"} 1`,
+		`pomo_intervals_completed_total{category="Pomodoro"} 1`,
+		`pomo_intervals_completed_total{category="ShortBreak"} 1`,
+		`pomo_intervals_cancelled_total{category="Pomodoro"} 1`,
+		`pomo_interval_state{state="running"} 1`,
+		`pomo_interval_state{state="done"} 0`,
+		`pomo_interval_remaining_seconds 900`,
+		// 27 минут - в корзину 30m и выше
+		`pomo_pomodoro_duration_seconds_bucket{category="Pomodoro",le="1500"} 0`,
+		`pomo_pomodoro_duration_seconds_bucket{category="Pomodoro",le="1800"} 1`,
+		`pomo_pomodoro_duration_seconds_bucket{category="Pomodoro",le="+Inf"} 1`,
+		`pomo_pomodoro_duration_seconds_sum{category="Pomodoro"} 1620`,
+		`pomo_pomodoro_duration_seconds_count{category="Pomodoro"} 1`,
+	} {
+		if !strings.Contains(out.String(), exp+"\n") {
+			t.Errorf("Ожидали строку %q в:\n%s", exp, out.String())
+		}
+	}
+
+	// Перерывы в гистограмму не попадают
+	if strings.Contains(out.String(), `duration_seconds_count{category="ShortBreak"}`) {
+		t.Errorf("Перерыв попал в гистограмму:\n%s", out.String())
+	}
+}
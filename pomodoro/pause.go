@@ -0,0 +1,150 @@
+package pomodoro
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Что делать с интервалом, простоявшим на паузе дольше IntevalConfig.MaxPause
+const (
+	// Отменить - интервал не засчитывается
+	PauseActionCancel = "cancel"
+	// Начать заново - интервал снова не начат, с полной продолжительностью
+	PauseActionReset = "reset"
+)
+
+var ErrInvalidPauseAction = errors.New("неизвестное действие по истечении паузы")
+
+// Отрезок паузы. У продолжающейся паузы End нулевой.
+type PauseSegment struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Продолжительность паузы; продолжающаяся считается до now
+func (p PauseSegment) Duration(now time.Time) time.Duration {
+	if p.End.IsZero() {
+		return now.Sub(p.Start)
+	}
+	return p.End.Sub(p.Start)
+}
+
+// Сколько интервал провёл на паузе; продолжающаяся пауза считается до текущего момента
+func (i Interval) PausedTime() time.Duration {
+	now := time.Now()
+	var d time.Duration
+	for _, p := range i.Pauses {
+		d += p.Duration(now)
+	}
+	return d
+}
+
+// Время по часам от старта до конца (или до текущего момента) - работа вместе с паузами.
+// 25-минутный Pomodoro с часом перерыва на обед займёт здесь 1ч25м.
+func (i Interval) WallClock() time.Duration {
+	return i.ActualDuration + i.PausedTime()
+}
+
+// Начинает отрезок паузы
+func (i *Interval) beginPause(now time.Time) {
+	// Clip - чтобы append не записал в общий с репозиторием массив
+	i.Pauses = append(slices.Clip(i.Pauses), PauseSegment{Start: now})
+}
+
+// Закрывает продолжающуюся паузу, если она есть
+func (i *Interval) endPause(now time.Time) {
+	if n := len(i.Pauses); n > 0 && i.Pauses[n-1].End.IsZero() {
+		i.Pauses = slices.Clone(i.Pauses)
+		i.Pauses[n-1].End = now
+	}
+}
+
+// Проверяет ограничение паузы: неотрицательная продолжительность
+// и известное действие (пустое - то же, что cancel)
+func ValidatePauseLimit(maxPause time.Duration, action string) error {
+	if maxPause < 0 {
+		return fmt.Errorf("%w: отрицательная максимальная пауза %s", ErrInvalidConfig, maxPause)
+	}
+	switch action {
+	case "", PauseActionCancel, PauseActionReset:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q, допустимо: %s, %s", ErrInvalidPauseAction, action,
+			PauseActionCancel, PauseActionReset)
+	}
+}
+
+// Сколько ещё интервал может простоять на текущей паузе.
+// ok == false, если интервал не на паузе или пауза не ограничена.
+func (c *IntevalConfig) PauseLeft(i Interval, now time.Time) (left time.Duration, ok bool) {
+	n := len(i.Pauses)
+	if c.MaxPause <= 0 || i.State != StatePaused || n == 0 || !i.Pauses[n-1].End.IsZero() {
+		return 0, false
+	}
+	return c.MaxPause - i.Pauses[n-1].Duration(now), true
+}
+
+// Применяет ограничение паузы к текущему интервалу: если он простоял на паузе
+// дольше MaxPause - отменяет его или начинает заново, смотря по PauseAction.
+// Вызывается и раз в секунду из TUI, и при запуске - для интервала, который
+// остался на паузе с прошлого раза. Возвращает текущий интервал после проверки
+// и признак того, что ограничение сработало.
+func ExpirePause(config *IntevalConfig) (Interval, bool, error) {
+	i, err := config.repo.Last()
+	if errors.Is(err, ErrNoIntervals) {
+		return i, false, nil
+	}
+	if err != nil {
+		return i, false, err
+	}
+
+	if left, ok := config.PauseLeft(i, time.Now()); !ok || left > 0 {
+		return i, false, nil
+	}
+
+	event := EventCancel
+	i, err = config.update(i.ID, func(i *Interval) error {
+		// Пока проверяли, интервал могли продолжить
+		if left, ok := config.PauseLeft(*i, time.Now()); !ok || left > 0 {
+			return errUnchanged
+		}
+		if config.PauseAction == PauseActionReset {
+			event = EventReset
+			i.reset(time.Now())
+			return nil
+		}
+		// Пауза в истории заканчивается на пределе, а не в момент проверки -
+		// при запуске через сутки иначе записали бы суточную паузу
+		event = EventCancel
+		n := len(i.Pauses)
+		i.Pauses = slices.Clone(i.Pauses)
+		i.Pauses[n-1].End = i.Pauses[n-1].Start.Add(config.MaxPause)
+		i.State = StateCancelled
+		return nil
+	})
+	if errors.Is(err, errUnchanged) {
+		return i, false, nil
+	}
+	if err != nil {
+		return i, false, err
+	}
+	config.emit(event, i)
+	return i, true, nil
+}
+
+// Начинает интервал заново: отработанное время, паузы и прерывания сброшены.
+// Исполняющийся продолжает исполняться с момента now,
+// приостановленный - снова не начат.
+func (i *Interval) reset(now time.Time) {
+	if i.State == StateRunning {
+		i.StartTime = now
+	} else {
+		i.State = StateNotStarted
+		i.StartTime = time.Time{}
+	}
+	i.ActualDuration = 0
+	i.Pauses = nil
+	i.Interruptions = nil
+}
@@ -0,0 +1,221 @@
+package pomodoro_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestExpirePause(t *testing.T) {
+	const duration = 25 * time.Minute
+
+	testCases := []struct {
+		name       string
+		maxPause   time.Duration
+		action     string
+		state      int
+		pausedFor  time.Duration
+		expExpired bool
+		expState   int
+	}{
+		{name: "NoLimit", pausedFor: 5 * time.Hour,
+			state: pomodoro.StatePaused, expState: pomodoro.StatePaused},
+		{name: "WithinLimit", maxPause: time.Hour, pausedFor: 10 * time.Minute,
+			state: pomodoro.StatePaused, expState: pomodoro.StatePaused},
+		{name: "Running", maxPause: time.Minute,
+			state: pomodoro.StateRunning, expState: pomodoro.StateRunning},
+		{name: "Cancel", maxPause: time.Hour, pausedFor: 5 * time.Hour,
+			state: pomodoro.StatePaused, expExpired: true, expState: pomodoro.StateCancelled},
+		{name: "Reset", maxPause: time.Hour, action: pomodoro.PauseActionReset, pausedFor: 5 * time.Hour,
+			state: pomodoro.StatePaused, expExpired: true, expState: pomodoro.StateNotStarted},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, cleanup := getRepo(t)
+			defer cleanup()
+
+			config := pomodoro.NewConfig(repo, duration, duration, duration)
+			config.MaxPause = tc.maxPause
+			config.PauseAction = tc.action
+
+			i, err := pomodoro.GetInterval(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Интервал поработал 10 минут и остался на паузе - как после перезапуска
+			now := time.Now()
+			i.State = tc.state
+			i.StartTime = now.Add(-tc.pausedFor - 10*time.Minute)
+			i.ActualDuration = 10 * time.Minute
+			if tc.state == pomodoro.StatePaused {
+				i.Pauses = []pomodoro.PauseSegment{{Start: now.Add(-tc.pausedFor)}}
+			}
+			if err := repo.Update(i); err != nil {
+				t.Fatal(err)
+			}
+
+			events := []string{}
+			config.Subscribe(func(e pomodoro.Event) { events = append(events, e.Type) })
+
+			i, expired, err := pomodoro.ExpirePause(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expired != tc.expExpired {
+				t.Errorf("Ожидали срабатывание: %t, а получили: %t", tc.expExpired, expired)
+			}
+			if i.State != tc.expState {
+				t.Errorf("Ожидали состояние: %q, а получили: %q",
+					pomodoro.StateName(tc.expState), pomodoro.StateName(i.State))
+			}
+
+			switch {
+			case !tc.expExpired:
+				if len(events) != 0 {
+					t.Errorf("Событий быть не должно, а получили: %v", events)
+				}
+			case tc.expState == pomodoro.StateCancelled:
+				// Пауза в истории заканчивается на пределе
+				if len(i.Pauses) != 1 || i.Pauses[0].Duration(now) != tc.maxPause {
+					t.Errorf("Ожидали паузу длиной %q, а получили: %+v", tc.maxPause, i.Pauses)
+				}
+				if len(events) != 1 || events[0] != pomodoro.EventCancel {
+					t.Errorf("Ожидали событие cancel, а получили: %v", events)
+				}
+			default:
+				if i.ActualDuration != 0 || !i.StartTime.IsZero() || len(i.Pauses) != 0 {
+					t.Errorf("Ожидали интервал с чистого листа, а получили: %+v", i)
+				}
+				if len(events) != 1 || events[0] != pomodoro.EventReset {
+					t.Errorf("Ожидали событие reset, а получили: %v", events)
+				}
+			}
+		})
+	}
+}
+
+// Продолжить интервал, простоявший на паузе дольше предела, нельзя:
+// Start и GetInterval сначала применяют ограничение паузы
+func TestStartAfterPauseLimit(t *testing.T) {
+	const duration = 25 * time.Minute
+
+	testCases := []struct {
+		name      string
+		action    string
+		expErr    error
+		expEvents []string
+	}{
+		{name: "Cancel", action: pomodoro.PauseActionCancel, expErr: pomodoro.ErrIntervalCompleted,
+			expEvents: []string{pomodoro.EventCancel}},
+		{name: "Reset", action: pomodoro.PauseActionReset,
+			expEvents: []string{pomodoro.EventReset, pomodoro.EventStart, pomodoro.EventPause}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, cleanup := getRepo(t)
+			defer cleanup()
+
+			config := pomodoro.NewConfig(repo, duration, duration, duration)
+			config.MaxPause = time.Hour
+			config.PauseAction = tc.action
+
+			i, err := pomodoro.GetInterval(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			now := time.Now()
+			i.State = pomodoro.StatePaused
+			i.StartTime = now.Add(-2 * time.Hour)
+			i.ActualDuration = 10 * time.Minute
+			i.Pauses = []pomodoro.PauseSegment{{Start: now.Add(-2*time.Hour + 10*time.Minute)}}
+			if err := repo.Update(i); err != nil {
+				t.Fatal(err)
+			}
+			if i, err = repo.ByID(i.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			events := []string{}
+			config.Subscribe(func(e pomodoro.Event) { events = append(events, e.Type) })
+
+			// Запустившийся интервал сразу ставим на паузу - чтобы Start вернулся
+			pause := func(i pomodoro.Interval) {
+				if err := i.Pause(config); err != nil {
+					t.Error(err)
+				}
+			}
+			noop := func(pomodoro.Interval) {}
+			if err := i.Start(context.Background(), config, pause, noop, noop); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Ожидали ошибку: %v, а получили: %v", tc.expErr, err)
+			}
+			if !slices.Equal(events, tc.expEvents) {
+				t.Errorf("Ожидали события: %v, а получили: %v", tc.expEvents, events)
+			}
+			if i, err = repo.ByID(i.ID); err != nil {
+				t.Fatal(err)
+			}
+			if tc.expErr == nil && i.ActualDuration >= 10*time.Minute {
+				t.Errorf("Ожидали интервал, начатый заново, а получили: %+v", i)
+			}
+
+			// Следующий интервал - новый, отменённый не возвращается
+			next, err := pomodoro.GetInterval(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (next.ID != i.ID) != (tc.expErr != nil) {
+				t.Errorf("Ожидали интервал %d: новый - %t, а получили: %d", i.ID, tc.expErr != nil, next.ID)
+			}
+		})
+	}
+}
+
+func TestPauseLeft(t *testing.T) {
+	config := pomodoro.NewConfig(nil, 0, 0, 0)
+	config.MaxPause = 10 * time.Minute
+
+	now := time.Now()
+	i := pomodoro.Interval{
+		State:  pomodoro.StatePaused,
+		Pauses: []pomodoro.PauseSegment{{Start: now.Add(-4 * time.Minute)}},
+	}
+	left, ok := config.PauseLeft(i, now)
+	if !ok || left != 6*time.Minute {
+		t.Errorf("Ожидали остаток: %q, а получили: %q (%t)", 6*time.Minute, left, ok)
+	}
+
+	i.State = pomodoro.StateRunning
+	if _, ok := config.PauseLeft(i, now); ok {
+		t.Error("У интервала не на паузе остатка паузы быть не должно")
+	}
+}
+
+func TestValidatePauseLimit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		maxPause time.Duration
+		action   string
+		expErr   error
+	}{
+		{name: "Default"},
+		{name: "Reset", maxPause: time.Hour, action: pomodoro.PauseActionReset},
+		{name: "Negative", maxPause: -time.Minute, expErr: pomodoro.ErrInvalidConfig},
+		{name: "Unknown", maxPause: time.Hour, action: "forget", expErr: pomodoro.ErrInvalidPauseAction},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := pomodoro.ValidatePauseLimit(tc.maxPause, tc.action)
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("Ожидали ошибку: %v, а получили: %v", tc.expErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package pomodoro
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Имя профиля по умолчанию - настройки из флагов и верхнего уровня конфиг-файла
+const DefaultProfile = "default"
+
+// Профиль - именованный набор настроек интервалов.
+// Например, разные ритмы для программирования, учёбы и встреч.
+type Profile struct {
+	Name               string
+	PomodoroDuration   time.Duration
+	ShortBreakDuration time.Duration
+	LongBreakDuration  time.Duration
+	Overtime           bool
+	Mode               string
+	FlowtimeRatios     []FlowtimeRatio
+	Schedule           Schedule
+}
+
+// Действующее расписание профиля: собственное, если задано, иначе - классическое
+func (p Profile) schedule() Schedule {
+	if len(p.Schedule.Sequence) > 0 {
+		return p.Schedule
+	}
+	return ClassicSchedule(p.PomodoroDuration, p.ShortBreakDuration, p.LongBreakDuration)
+}
+
+// Сколько последних интервалов нужно для планирования следующего:
+// для последовательности - её длина, для Flowtime - только последний
+func (p Profile) history() int {
+	if p.Mode == ModeFlowtime {
+		return 1
+	}
+	return len(p.schedule().Sequence)
+}
+
+// Планирует следующий интервал по истории recent (самый свежий - первый)
+func (p Profile) plan(recent []Interval) Interval {
+	var i Interval
+	if p.Mode == ModeFlowtime {
+		i = p.planFlowtime(recent)
+	} else {
+		t := p.schedule().next(recent)
+		i = Interval{Category: t.Category, PlannedDuration: t.Duration, Break: t.Break}
+	}
+	i.Profile = p.Name
+	return i
+}
+
+// Снимок действующих настроек конфига в виде профиля
+func (c *IntevalConfig) current() Profile {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Profile{
+		Name:               c.Profile,
+		PomodoroDuration:   c.PomodoroDuration,
+		ShortBreakDuration: c.ShortBreakDuration,
+		LongBreakDuration:  c.LongBreakDuration,
+		Overtime:           c.Overtime,
+		Mode:               c.Mode,
+		FlowtimeRatios:     c.FlowtimeRatios,
+		Schedule:           c.Schedule,
+	}
+}
+
+// Имя действующего профиля
+func (c *IntevalConfig) ActiveProfile() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.Profile
+}
+
+// Регистрирует профили, между которыми можно переключаться
+func (c *IntevalConfig) SetProfiles(profiles []Profile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.profiles = profiles
+}
+
+// Возвращает зарегистрированные профили. Если профили не регистрировались -
+// единственный профиль составляют действующие настройки.
+func (c *IntevalConfig) Profiles() []Profile {
+	c.mu.RLock()
+	profiles := c.profiles
+	c.mu.RUnlock()
+
+	if len(profiles) == 0 {
+		return []Profile{c.current()}
+	}
+	return append([]Profile{}, profiles...)
+}
+
+// Делает профиль name действующим - копирует его настройки в конфиг.
+// Уже созданные интервалы это не затрагивает.
+func (c *IntevalConfig) UseProfile(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, p := range c.profiles {
+		if p.Name != name {
+			continue
+		}
+		c.Profile = p.Name
+		c.PomodoroDuration = p.PomodoroDuration
+		c.ShortBreakDuration = p.ShortBreakDuration
+		c.LongBreakDuration = p.LongBreakDuration
+		c.Overtime = p.Overtime
+		c.Mode = p.Mode
+		c.FlowtimeRatios = p.FlowtimeRatios
+		c.Schedule = p.Schedule
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownProfile, name)
+}
+
+// Переключает профиль между интервалами. Исполняющийся или приостановленный
+// интервал доработает по старому профилю - переключаться можно только после него.
+// Созданный, но ещё не начатый интервал перепланируется по новому профилю.
+func SwitchProfile(config *IntevalConfig, name string) error {
+	li, lastErr := config.repo.Last()
+	if lastErr != nil && lastErr != ErrNoIntervals {
+		return lastErr
+	}
+	hasLast := lastErr == nil
+
+	if hasLast && (li.State == StateRunning || li.State == StatePaused) {
+		return fmt.Errorf("%w: профиль можно сменить только между интервалами", ErrIntervalActive)
+	}
+
+	if err := config.UseProfile(name); err != nil {
+		return err
+	}
+
+	if !hasLast || li.State != StateNotStarted {
+		return nil
+	}
+	return replan(config, li)
+}
+
+// Применяет новый набор профилей - например, после правки конфиг-файла.
+// Действующий профиль сохраняется, если он остался в наборе, иначе -
+// действующим становится первый профиль набора, а вызывающий получит
+// ErrUnknownProfile. Исполняющийся интервал изменения не затрагивают,
+// созданный, но не начатый - перепланируется по новым настройкам.
+func ReloadProfiles(config *IntevalConfig, profiles []Profile) error {
+	if len(profiles) == 0 {
+		return fmt.Errorf("%w: пустой набор профилей", ErrInvalidProfile)
+	}
+
+	li, lastErr := config.repo.Last()
+	if lastErr != nil && lastErr != ErrNoIntervals {
+		return lastErr
+	}
+
+	config.SetProfiles(profiles)
+	useErr := config.UseProfile(config.ActiveProfile())
+	if useErr != nil {
+		if err := config.UseProfile(profiles[0].Name); err != nil {
+			return err
+		}
+	}
+
+	if lastErr == nil && li.State == StateNotStarted {
+		if err := replan(config, li); err != nil {
+			return err
+		}
+	}
+	return useErr
+}
+
+// Перепланирует неначатый интервал li по действующим настройкам -
+// по истории без него самого
+func replan(config *IntevalConfig, li Interval) error {
+	p := config.current()
+	recent, err := config.repo.Recent(p.history() + 1)
+	if err != nil {
+		return err
+	}
+	next := p.plan(recent[1:])
+	_, err = config.update(li.ID, func(i *Interval) error {
+		// Интервал успели запустить - его уже не трогаем
+		if i.State != StateNotStarted {
+			return errUnchanged
+		}
+		next.ID, next.Version = i.ID, i.Version
+		*i = next
+		return nil
+	})
+	if errors.Is(err, errUnchanged) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+package pomodoro_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestUseProfile(t *testing.T) {
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, 0, 0, 0)
+	config.SetProfiles([]pomodoro.Profile{
+		{Name: pomodoro.DefaultProfile, PomodoroDuration: 25 * time.Minute,
+			ShortBreakDuration: 5 * time.Minute, LongBreakDuration: 15 * time.Minute,
+			Mode: pomodoro.ModeClassic},
+		{Name: "coding", PomodoroDuration: 50 * time.Minute,
+			ShortBreakDuration: 10 * time.Minute, LongBreakDuration: 30 * time.Minute,
+			Mode: pomodoro.ModeClassic, Overtime: true},
+	})
+
+	if err := config.UseProfile("meetings"); !errors.Is(err, pomodoro.ErrUnknownProfile) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrUnknownProfile, err)
+	}
+
+	if err := config.UseProfile("coding"); err != nil {
+		t.Fatal(err)
+	}
+	if config.ActiveProfile() != "coding" || config.PomodoroDuration != 50*time.Minute || !config.Overtime {
+		t.Errorf("Ожидали настройки профиля coding, а получили: %+v", *config)
+	}
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.Profile != "coding" || i.PlannedDuration != 50*time.Minute {
+		t.Errorf("Ожидали интервал профиля coding, а получили: %+v", i)
+	}
+}
+
+func TestSwitchProfile(t *testing.T) {
+	const duration = 2 * time.Second
+
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	config := pomodoro.NewConfig(repo, duration, duration, duration)
+	config.SetProfiles([]pomodoro.Profile{
+		{Name: pomodoro.DefaultProfile, PomodoroDuration: duration,
+			ShortBreakDuration: duration, LongBreakDuration: duration,
+			Mode: pomodoro.ModeClassic},
+		{Name: "short", PomodoroDuration: duration / 2,
+			ShortBreakDuration: duration / 2, LongBreakDuration: duration / 2,
+			Mode: pomodoro.ModeClassic},
+	})
+
+	// Созданный, но не начатый интервал перепланируется по новому профилю
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pomodoro.SwitchProfile(config, "short"); err != nil {
+		t.Fatal(err)
+	}
+	i, err = repo.ByID(i.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.Profile != "short" || i.PlannedDuration != duration/2 {
+		t.Errorf("Ожидали перепланированный интервал профиля short, а получили: %+v", i)
+	}
+
+	// Приостановленный интервал дорабатывает по старому профилю
+	if err := pomodoro.SwitchProfile(config, pomodoro.DefaultProfile); err != nil {
+		t.Fatal(err)
+	}
+	i, err = pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emptyF := func(pomodoro.Interval) {}
+	pause := func(i pomodoro.Interval) {
+		if err := i.Pause(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := i.Start(context.Background(), config, emptyF, pause, emptyF); err != nil {
+		t.Fatal(err)
+	}
+
+	err = pomodoro.SwitchProfile(config, "short")
+	if !errors.Is(err, pomodoro.ErrIntervalActive) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrIntervalActive, err)
+	}
+	if config.ActiveProfile() != pomodoro.DefaultProfile {
+		t.Errorf("Ожидали профиль: %q, а получили: %q", pomodoro.DefaultProfile, config.ActiveProfile())
+	}
+}
+
+func TestReloadProfiles(t *testing.T) {
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	profile := func(name string, d time.Duration) pomodoro.Profile {
+		return pomodoro.Profile{Name: name, PomodoroDuration: d, ShortBreakDuration: d,
+			LongBreakDuration: d, Mode: pomodoro.ModeClassic}
+	}
+
+	config := pomodoro.NewConfig(repo, 0, 0, 0)
+	config.SetProfiles([]pomodoro.Profile{
+		profile(pomodoro.DefaultProfile, time.Minute),
+		profile("coding", 2*time.Minute),
+	})
+	if err := config.UseProfile("coding"); err != nil {
+		t.Fatal(err)
+	}
+
+	i, err := pomodoro.GetInterval(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Профиль coding изменился в конфиге - неначатый интервал перепланируется
+	err = pomodoro.ReloadProfiles(config, []pomodoro.Profile{
+		profile(pomodoro.DefaultProfile, time.Minute),
+		profile("coding", 3*time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i, err = repo.ByID(i.ID); err != nil {
+		t.Fatal(err)
+	}
+	if config.ActiveProfile() != "coding" || i.PlannedDuration != 3*time.Minute {
+		t.Errorf("Ожидали интервал профиля coding на 3 минуты, а получили: %+v", i)
+	}
+
+	// Профиль coding удалили из конфига - действующим становится первый
+	err = pomodoro.ReloadProfiles(config, []pomodoro.Profile{
+		profile(pomodoro.DefaultProfile, time.Minute),
+	})
+	if !errors.Is(err, pomodoro.ErrUnknownProfile) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrUnknownProfile, err)
+	}
+	if config.ActiveProfile() != pomodoro.DefaultProfile {
+		t.Errorf("Ожидали профиль: %q, а получили: %q", pomodoro.DefaultProfile, config.ActiveProfile())
+	}
+}
@@ -0,0 +1,109 @@
+package pomodoro
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var ErrInvalidDate = errors.New("неверная дата")
+
+// Имена состояний для выгрузки и API
+var stateNames = map[int]string{
+	StateNotStarted: "not_started",
+	StateRunning:    "running",
+	StatePaused:     "paused",
+	StateDone:       "done",
+	StateCancelled:  "cancelled",
+}
+
+// Имя состояния интервала
+func StateName(state int) string {
+	if n, ok := stateNames[state]; ok {
+		return n
+	}
+	return fmt.Sprintf("unknown(%d)", state)
+}
+
+// Состояние интервала по имени
+func ParseState(name string) (int, error) {
+	for s, n := range stateNames {
+		if n == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidState, name)
+}
+
+// Фильтр интервалов
+type Filter struct {
+	// Интервалы, начатые в промежутке [From, To). Нулевой To - без ограничения.
+	From time.Time
+	To   time.Time
+	// Категории - если пусто, то любые
+	Categories []string
+	// Метки - интервал должен иметь хотя бы одну из них; если пусто, то любые
+	Tags []string
+}
+
+// Подходит ли интервал под фильтр по категории и меткам.
+// Промежуток времени проверяет репозиторий в Range.
+func (f Filter) Match(i Interval) bool {
+	if len(f.Categories) > 0 && !slices.Contains(f.Categories, i.Category) {
+		return false
+	}
+	if len(f.Tags) > 0 && !slices.ContainsFunc(f.Tags, func(t string) bool {
+		return slices.Contains(i.Tags, t)
+	}) {
+		return false
+	}
+	return true
+}
+
+// Возвращает начатые интервалы, подходящие под фильтр, в порядке начала
+func Query(r Repository, f Filter) ([]Interval, error) {
+	to := f.To
+	if to.IsZero() {
+		// Без ограничения сверху - с запасом, чтобы захватить исполняющийся интервал
+		to = time.Now().Add(24 * time.Hour)
+	}
+
+	intervals, err := r.Range(f.From, to)
+	if err != nil {
+		return nil, err
+	}
+
+	returnData := []Interval{}
+	for _, i := range intervals {
+		if f.Match(i) {
+			returnData = append(returnData, i)
+		}
+	}
+	slices.SortStableFunc(returnData, func(a, b Interval) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+	return returnData, nil
+}
+
+// Разбирает дату в формате 2006-01-02 (локальное время) или RFC 3339 - для
+// границ фильтра. Для конца промежутка дата без времени включает весь день -
+// возвращаем начало следующего. Пустая строка - нулевое время (без ограничения).
+func ParseDate(s string, end bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w %q: ожидали 2006-01-02 или RFC 3339", ErrInvalidDate, s)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
@@ -0,0 +1,105 @@
+package pomodoro_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestQuery(t *testing.T) {
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local)
+	intervals := []pomodoro.Interval{
+		{StartTime: day.Add(9 * time.Hour), Category: pomodoro.CategoryPomodoro, Tags: []string{"work"}},
+		{StartTime: day.Add(10 * time.Hour), Category: pomodoro.CategoryShortBreak, Break: true},
+		{StartTime: day.Add(33 * time.Hour), Category: pomodoro.CategoryPomodoro, Tags: []string{"home"}},
+		// Неначатый интервал в выборку не попадает никогда
+		{Category: pomodoro.CategoryPomodoro, Tags: []string{"work"}},
+	}
+	// Создаём в обратном порядке - Query должен вернуть по времени начала
+	for k := len(intervals) - 1; k >= 0; k-- {
+		if _, err := repo.Create(intervals[k]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		filter pomodoro.Filter
+		expect []time.Time
+	}{
+		{
+			name:   "All",
+			filter: pomodoro.Filter{},
+			expect: []time.Time{day.Add(9 * time.Hour), day.Add(10 * time.Hour), day.Add(33 * time.Hour)},
+		},
+		{
+			name:   "Day",
+			filter: pomodoro.Filter{From: day, To: day.AddDate(0, 0, 1)},
+			expect: []time.Time{day.Add(9 * time.Hour), day.Add(10 * time.Hour)},
+		},
+		{
+			name:   "Category",
+			filter: pomodoro.Filter{Categories: []string{pomodoro.CategoryPomodoro}},
+			expect: []time.Time{day.Add(9 * time.Hour), day.Add(33 * time.Hour)},
+		},
+		{
+			name:   "Tag",
+			filter: pomodoro.Filter{Tags: []string{"home", "other"}},
+			expect: []time.Time{day.Add(33 * time.Hour)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := pomodoro.Query(repo, tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res) != len(tc.expect) {
+				t.Fatalf("Ожидали интервалов: %d, а получили: %d", len(tc.expect), len(res))
+			}
+			for k, i := range res {
+				if !i.StartTime.Equal(tc.expect[k]) {
+					t.Errorf("Ожидали начало: %v, а получили: %v", tc.expect[k], i.StartTime)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		name   string
+		input  string
+		end    bool
+		expect time.Time
+		expErr error
+	}{
+		{name: "Empty", input: "", expect: time.Time{}},
+		{name: "Date", input: "2025-03-14", expect: day},
+		// Конец промежутка включает весь день
+		{name: "DateEnd", input: "2025-03-14", end: true, expect: day.AddDate(0, 0, 1)},
+		{name: "RFC3339", input: "2025-03-14T09:30:00Z", end: true,
+			expect: time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)},
+		{name: "Invalid", input: "вчера", expErr: pomodoro.ErrInvalidDate},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := pomodoro.ParseDate(tc.input, tc.end)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("Ожидали ошибку: %v, а получили: %v", tc.expErr, err)
+			}
+			if !got.Equal(tc.expect) {
+				t.Errorf("Ожидали: %s, а получили: %s", tc.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package pomodoro
+
+import "time"
+
+// Сводка по одной категории интервалов
+type CategoryReport struct {
+	// Количество интервалов категории
+	Count int
+	// Сколько из них завершено
+	Done int
+	// Время, отработанное в пределах запланированного
+	Planned time.Duration
+	// Время переработки - сверх запланированного
+	Overtime time.Duration
+	// Прерывания: внутренние и внешние
+	Internal int
+	External int
+	// Паузы: сколько раз и сколько времени всего
+	Pauses int
+	Paused time.Duration
+}
+
+// Итоговое время категории - плановое плюс переработка
+func (c CategoryReport) Total() time.Duration {
+	return c.Planned + c.Overtime
+}
+
+// Отчёт по набору интервалов
+type Report struct {
+	Categories map[string]CategoryReport
+	// Итоги по всем рабочим интервалам и по всем перерывам
+	Work   CategoryReport
+	Breaks CategoryReport
+}
+
+// Формирует отчёт по интервалам. Неначатые интервалы в отчёт не попадают.
+func NewReport(intervals []Interval) Report {
+	r := Report{Categories: map[string]CategoryReport{}}
+
+	for _, i := range intervals {
+		if i.State == StateNotStarted {
+			continue
+		}
+
+		r.Categories[i.Category] = r.Categories[i.Category].add(i)
+		if i.Break {
+			r.Breaks = r.Breaks.add(i)
+		} else {
+			r.Work = r.Work.add(i)
+		}
+	}
+
+	return r
+}
+
+// Добавляет интервал в сводку
+func (c CategoryReport) add(i Interval) CategoryReport {
+	c.Count++
+	if i.State == StateDone {
+		c.Done++
+	}
+	overtime := i.Overtime()
+	c.Planned += i.ActualDuration - overtime
+	c.Overtime += overtime
+	c.Internal += i.InterruptionCount(InterruptionInternal)
+	c.External += i.InterruptionCount(InterruptionExternal)
+	c.Pauses += len(i.Pauses)
+	c.Paused += i.PausedTime()
+	return c
+}
+
+// Отчёт за сутки, в которые попадает day (по локальному времени)
+func DailyReport(config *IntevalConfig, day time.Time) (Report, error) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	intervals, err := config.repo.Range(from, from.AddDate(0, 0, 1))
+	if err != nil {
+		return Report{}, err
+	}
+	return NewReport(intervals), nil
+}
@@ -0,0 +1,71 @@
+package pomodoro_test
+
+import (
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestNewReport(t *testing.T) {
+	intervals := []pomodoro.Interval{
+		{
+			Category:        pomodoro.CategoryPomodoro,
+			State:           pomodoro.StateDone,
+			PlannedDuration: 25 * time.Minute,
+			ActualDuration:  25 * time.Minute,
+		},
+		{
+			// Переработка 5 минут и прерывания
+			Category:        pomodoro.CategoryPomodoro,
+			State:           pomodoro.StateDone,
+			PlannedDuration: 25 * time.Minute,
+			ActualDuration:  30 * time.Minute,
+			Interruptions: []pomodoro.Interruption{
+				{Kind: pomodoro.InterruptionInternal},
+				{Kind: pomodoro.InterruptionExternal},
+				{Kind: pomodoro.InterruptionInternal},
+			},
+		},
+		{
+			// Отменённый - считается, но не завершён
+			Category:        pomodoro.CategoryPomodoro,
+			State:           pomodoro.StateCancelled,
+			PlannedDuration: 25 * time.Minute,
+			ActualDuration:  10 * time.Minute,
+		},
+		{
+			// Неначатый - в отчёт не попадает
+			Category:        pomodoro.CategoryShortBreak,
+			Break:           true,
+			State:           pomodoro.StateNotStarted,
+			PlannedDuration: 5 * time.Minute,
+		},
+	}
+
+	r := pomodoro.NewReport(intervals)
+
+	c := r.Categories[pomodoro.CategoryPomodoro]
+	expect := pomodoro.CategoryReport{
+		Count:    3,
+		Done:     2,
+		Planned:  60 * time.Minute,
+		Overtime: 5 * time.Minute,
+		Internal: 2,
+		External: 1,
+	}
+	if c != expect {
+		t.Errorf("Ожидали: %+v, а получили: %+v", expect, c)
+	}
+	if c.Total() != 65*time.Minute {
+		t.Errorf("Ожидали итого: %q, а получили: %q", 65*time.Minute, c.Total())
+	}
+
+	if r.Work != expect {
+		t.Errorf("Ожидали итог по работе: %+v, а получили: %+v", expect, r.Work)
+	}
+
+	if _, ok := r.Categories[pomodoro.CategoryShortBreak]; ok {
+		t.Errorf("Неначатый интервал не должен попадать в отчёт")
+	}
+}
@@ -3,7 +3,9 @@ package repository
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
+	"time"
 
 	"vegorov.ru/go-cli/pomo/pomodoro"
 )
@@ -14,6 +16,8 @@ type inMemoryRepo struct {
 	sync.RWMutex
 	// Интервалы хранятся в слайсе intervals, доступ к - семафорим через RWMutex
 	intervals []pomodoro.Interval
+	// Задачи - так же, ID задачи - её номер в слайсе
+	tasks []pomodoro.Task
 }
 
 func NewInMemoryRepo() *inMemoryRepo {
@@ -38,6 +42,20 @@ func (r *inMemoryRepo) Create(i pomodoro.Interval) (int64, error) {
 	return i.ID, nil
 }
 
+// Записывает интервалы в репозиторий, возвращает их ID
+func (r *inMemoryRepo) CreateMany(intervals []pomodoro.Interval) ([]int64, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	ids := make([]int64, 0, len(intervals))
+	for _, i := range intervals {
+		i.ID = int64(len(r.intervals)) + 1
+		r.intervals = append(r.intervals, i)
+		ids = append(ids, i.ID)
+	}
+	return ids, nil
+}
+
 // Обновляет интервал в репозитории
 func (r *inMemoryRepo) Update(i pomodoro.Interval) error {
 	r.Lock()
@@ -47,7 +65,13 @@ func (r *inMemoryRepo) Update(i pomodoro.Interval) error {
 		return fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, i.ID)
 	}
 
+	// Интервал изменили после того, как i прочитали, - не затираем
+	if r.intervals[i.ID-1].Version != i.Version {
+		return fmt.Errorf("%w: %d", pomodoro.ErrConflict, i.ID)
+	}
+
 	// Заменяем в слайсе значение на новое - которое пришло в параметре i
+	i.Version++
 	r.intervals[i.ID-1] = i
 	return nil
 }
@@ -74,29 +98,69 @@ func (r *inMemoryRepo) Last() (pomodoro.Interval, error) {
 	if len(r.intervals) == 0 {
 		return i, pomodoro.ErrNoIntervals
 	}
-	return r.intervals[len(r.intervals)-1], nil
+	return newest(r.intervals, 1)[0], nil
 }
 
-// Возвращает n последних перерывов из репозитория
-func (r *inMemoryRepo) Breaks(n int) ([]pomodoro.Interval, error) {
-	r.Lock()
-	defer r.Unlock()
+// Возвращает n последних интервалов из репозитория, самый свежий - первый
+func (r *inMemoryRepo) Recent(n int) ([]pomodoro.Interval, error) {
+	r.RLock()
+	defer r.RUnlock()
 
-	// Пустышка для накопления данных для возврата
-	returnData := []pomodoro.Interval{}
+	return newest(r.intervals, n), nil
+}
+
+// Возвращает интервалы, начатые в промежутке [from, to)
+func (r *inMemoryRepo) Range(from, to time.Time) ([]pomodoro.Interval, error) {
+	r.RLock()
+	defer r.RUnlock()
 
-	for k := len(r.intervals) - 1; k >= 0; k-- {
-		if r.intervals[k].Category == pomodoro.CategoryPomodoro {
-			// В категориях (типах) интервалов у нас есть только CategoryPomodoro (работа)
-			// и перерывы - поэтому скипаем работу, а если не скипнули - то это перерыв,
-			// а они-то нам и нужны.
+	returnData := []pomodoro.Interval{}
+	for _, i := range r.intervals {
+		// Неначатые интервалы имеют нулевой StartTime - их пропускаем
+		if i.StartTime.IsZero() || i.StartTime.Before(from) || !i.StartTime.Before(to) {
 			continue
 		}
-		// Накапливаем слайс с перерывами
-		returnData = append(returnData, r.intervals[k])
-		if len(returnData) == n {
-			return returnData, nil
-		}
+		returnData = append(returnData, i)
 	}
 	return returnData, nil
 }
+
+// Записывает задачу в репозиторий, возвращет ID задачи
+func (r *inMemoryRepo) CreateTask(t pomodoro.Task) (int64, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	t.ID = int64(len(r.tasks)) + 1
+	r.tasks = append(r.tasks, t)
+	return t.ID, nil
+}
+
+// Обновляет задачу в репозитории
+func (r *inMemoryRepo) UpdateTask(t pomodoro.Task) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if t.ID < 1 || t.ID > int64(len(r.tasks)) {
+		return fmt.Errorf("%w: #%d", pomodoro.ErrUnknownTask, t.ID)
+	}
+	r.tasks[t.ID-1] = t
+	return nil
+}
+
+func (r *inMemoryRepo) TaskByID(id int64) (pomodoro.Task, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	if id < 1 || id > int64(len(r.tasks)) {
+		return pomodoro.Task{}, fmt.Errorf("%w: #%d", pomodoro.ErrUnknownTask, id)
+	}
+	return r.tasks[id-1], nil
+}
+
+// Возвращает все задачи в порядке добавления
+func (r *inMemoryRepo) Tasks() ([]pomodoro.Task, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	return slices.Clone(r.tasks), nil
+}
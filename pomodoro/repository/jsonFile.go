@@ -0,0 +1,384 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Содержимое файла репозитория. Объект, а не просто массив интервалов -
+// чтобы в файл можно было добавлять другие данные, не ломая формат.
+type jsonFileData struct {
+	Version   int                 `json:"version"`
+	Intervals []pomodoro.Interval `json:"intervals"`
+	Tasks     []pomodoro.Task     `json:"tasks,omitempty"`
+}
+
+// Версия формата файла. Во второй версии появились задачи: прежний pomo
+// потерял бы их при записи, поэтому файл второй версии он читать откажется.
+const jsonFileVersion = 2
+
+// Репозиторий для хранения интервалов в JSON-файле.
+//
+// Файл целиком держим в памяти и разбираем заново, только если его изменил
+// кто-то другой (например, pomo в соседнем терминале). Чтение идёт на каждом
+// тике, поэтому сначала сверяем stat: тот же файл (pomo пишет через rename -
+// каждая запись это новый файл), тот же размер и время изменения - значит,
+// не менялся. Иначе читаем и сверяем хэш содержимого: время изменения
+// на некоторых файловых системах грубое, а touch содержимое не меняет.
+//
+// Файл пишут несколько процессов сразу - TUI, pomo serve, команды pomo, -
+// поэтому чтение, изменение и запись идут под блокировкой файла.
+// Пишем через временный файл и rename, чтобы при сбое не остаться
+// с обрезанным файлом.
+type jsonFileRepo struct {
+	sync.Mutex
+	path string
+	lock *fileLock
+	data jsonFileData
+	// Хэш и stat файла на момент последнего чтения или записи
+	sum    [sha256.Size]byte
+	info   fs.FileInfo
+	loaded bool
+	// Интервалы от самого свежего - для Last и Recent; nil - не сортированы
+	sorted []pomodoro.Interval
+}
+
+func NewJSONFileRepo(path string) (*jsonFileRepo, error) {
+	slog.Debug("Creating JSONFileRepo", "path", path)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	lock, err := newFileLock(path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &jsonFileRepo{path: path, lock: lock}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Перечитывает файл, если он изменился с прошлого раза. Вызывать под блокировкой.
+func (r *jsonFileRepo) load() error {
+	info, err := os.Stat(r.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		r.data = jsonFileData{Version: jsonFileVersion}
+		r.loaded, r.sorted = false, nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if r.loaded && sameFile(info, r.info) {
+		return nil
+	}
+
+	b, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(b)
+	if r.loaded && sum == r.sum {
+		r.info = info
+		return nil
+	}
+
+	data := jsonFileData{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return fmt.Errorf("%s: %w", r.path, err)
+	}
+	if data.Version > jsonFileVersion {
+		return fmt.Errorf("%s: неподдерживаемая версия формата %d", r.path, data.Version)
+	}
+
+	r.data = data
+	r.sum, r.info, r.loaded = sum, info, true
+	r.sorted = nil
+	return nil
+}
+
+// Тот же ли это файл без изменений. Stat берём до чтения: если файл
+// изменят между stat и чтением, следующий stat уже не совпадёт.
+func sameFile(a, b fs.FileInfo) bool {
+	return b != nil && os.SameFile(a, b) && a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
+
+// Изменяет данные функцией f и записывает файл. Файл блокируется на всё
+// время - иначе между чтением и записью его мог бы изменить другой процесс,
+// и его изменение затёрлось бы.
+func (r *jsonFileRepo) modify(f func() error) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.lock.Lock(); err != nil {
+		return err
+	}
+	defer r.lock.Unlock()
+
+	if err := r.load(); err != nil {
+		return err
+	}
+	r.sorted = nil
+	if err := f(); err != nil {
+		return err
+	}
+	if err := r.save(); err != nil {
+		// Данные в памяти изменены, а файл - нет: в следующий раз перечитаем
+		r.loaded = false
+		return err
+	}
+	return nil
+}
+
+// Записывает данные в файл. Вызывать под блокировкой.
+func (r *jsonFileRepo) save() error {
+	r.data.Version = jsonFileVersion
+	b, err := json.Marshal(r.data)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+	r.sum, r.info, r.loaded = sha256.Sum256(b), info, true
+	return nil
+}
+
+// Интервалы от самого свежего. Сортируем, только когда данные изменились.
+// Вызывать под блокировкой.
+func (r *jsonFileRepo) newest() []pomodoro.Interval {
+	if r.sorted == nil {
+		r.sorted = newest(r.data.Intervals, len(r.data.Intervals))
+	}
+	return r.sorted
+}
+
+// Индекс интервала в слайсе по ID
+func (r *jsonFileRepo) index(id int64) (int, error) {
+	for k, i := range r.data.Intervals {
+		if i.ID == id {
+			return k, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, id)
+}
+
+// Записывает интервал в репозиторий, возвращет ID в репозитории
+func (r *jsonFileRepo) Create(i pomodoro.Interval) (int64, error) {
+	err := r.modify(func() error {
+		// ID - на единицу больше максимального
+		i.ID = 1
+		for _, e := range r.data.Intervals {
+			i.ID = max(i.ID, e.ID+1)
+		}
+
+		r.data.Intervals = append(r.data.Intervals, i)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return i.ID, nil
+}
+
+// Записывает интервалы в репозиторий одной записью файла, возвращает их ID
+func (r *jsonFileRepo) CreateMany(intervals []pomodoro.Interval) ([]int64, error) {
+	ids := make([]int64, 0, len(intervals))
+	err := r.modify(func() error {
+		next := int64(1)
+		for _, e := range r.data.Intervals {
+			next = max(next, e.ID+1)
+		}
+		for _, i := range intervals {
+			i.ID = next
+			next++
+			r.data.Intervals = append(r.data.Intervals, i)
+			ids = append(ids, i.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Обновляет интервал в репозитории
+func (r *jsonFileRepo) Update(i pomodoro.Interval) error {
+	return r.modify(func() error {
+		k, err := r.index(i.ID)
+		if err != nil {
+			return err
+		}
+		// Интервал изменили после того, как i прочитали, - не затираем
+		if r.data.Intervals[k].Version != i.Version {
+			return fmt.Errorf("%w: %d", pomodoro.ErrConflict, i.ID)
+		}
+		i.Version++
+		r.data.Intervals[k] = i
+		return nil
+	})
+}
+
+func (r *jsonFileRepo) ByID(id int64) (pomodoro.Interval, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.load(); err != nil {
+		return pomodoro.Interval{}, err
+	}
+
+	k, err := r.index(id)
+	if err != nil {
+		return pomodoro.Interval{}, err
+	}
+	return r.data.Intervals[k], nil
+}
+
+// Возвращает последний интервал из репозитория
+func (r *jsonFileRepo) Last() (pomodoro.Interval, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.load(); err != nil {
+		return pomodoro.Interval{}, err
+	}
+
+	if len(r.data.Intervals) == 0 {
+		return pomodoro.Interval{}, pomodoro.ErrNoIntervals
+	}
+	return r.newest()[0], nil
+}
+
+// Возвращает n последних интервалов из репозитория, самый свежий - первый
+func (r *jsonFileRepo) Recent(n int) ([]pomodoro.Interval, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	sorted := r.newest()
+	return slices.Clone(sorted[:min(n, len(sorted))]), nil
+}
+
+// Возвращает интервалы, начатые в промежутке [from, to)
+func (r *jsonFileRepo) Range(from, to time.Time) ([]pomodoro.Interval, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	returnData := []pomodoro.Interval{}
+	for _, i := range r.data.Intervals {
+		// Неначатые интервалы имеют нулевой StartTime - их пропускаем
+		if i.StartTime.IsZero() || i.StartTime.Before(from) || !i.StartTime.Before(to) {
+			continue
+		}
+		returnData = append(returnData, i)
+	}
+	return returnData, nil
+}
+
+// Индекс задачи в слайсе по ID
+func (r *jsonFileRepo) taskIndex(id int64) (int, error) {
+	for k, t := range r.data.Tasks {
+		if t.ID == id {
+			return k, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: #%d", pomodoro.ErrUnknownTask, id)
+}
+
+// Записывает задачу в репозиторий, возвращет ID задачи
+func (r *jsonFileRepo) CreateTask(t pomodoro.Task) (int64, error) {
+	err := r.modify(func() error {
+		t.ID = 1
+		for _, e := range r.data.Tasks {
+			t.ID = max(t.ID, e.ID+1)
+		}
+
+		r.data.Tasks = append(r.data.Tasks, t)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return t.ID, nil
+}
+
+// Обновляет задачу в репозитории
+func (r *jsonFileRepo) UpdateTask(t pomodoro.Task) error {
+	return r.modify(func() error {
+		k, err := r.taskIndex(t.ID)
+		if err != nil {
+			return err
+		}
+		r.data.Tasks[k] = t
+		return nil
+	})
+}
+
+func (r *jsonFileRepo) TaskByID(id int64) (pomodoro.Task, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.load(); err != nil {
+		return pomodoro.Task{}, err
+	}
+
+	k, err := r.taskIndex(id)
+	if err != nil {
+		return pomodoro.Task{}, err
+	}
+	return r.data.Tasks[k], nil
+}
+
+// Возвращает все задачи в порядке добавления
+func (r *jsonFileRepo) Tasks() ([]pomodoro.Task, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(r.data.Tasks), nil
+}
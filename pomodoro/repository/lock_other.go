@@ -0,0 +1,14 @@
+//go:build !unix
+
+package repository
+
+// Вне unix flock нет, и записи разных процессов не упорядочены:
+// от затирания чужих изменений спасает только проверка версии интервала
+type fileLock struct{}
+
+func newFileLock(path string) (*fileLock, error) {
+	return &fileLock{}, nil
+}
+
+func (l *fileLock) Lock() error   { return nil }
+func (l *fileLock) Unlock() error { return nil }
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Репозиторий, который пишет ошибки вложенного репозитория в лог
+type loggingRepo struct {
+	repo pomodoro.Repository
+}
+
+// Репозиторий интервалов и задач с логом ошибок
+type loggingTaskRepo struct {
+	loggingRepo
+	tasks pomodoro.TaskRepository
+}
+
+// Оборачивает репозиторий: каждая его ошибка попадает в лог, а затем
+// возвращается как есть. "Интервалы отсутствуют" - не ошибка, а пустая история,
+// конфликт версий - тоже: изменение повторят на свежей копии.
+// Если репозиторий хранит и задачи - обёртка тоже их хранит.
+func WithLogging(repo pomodoro.Repository) pomodoro.Repository {
+	if tasks, ok := repo.(pomodoro.TaskRepository); ok {
+		return loggingTaskRepo{loggingRepo{repo: repo}, tasks}
+	}
+	return loggingRepo{repo: repo}
+}
+
+func logError(op string, err error, args ...any) {
+	if err == nil || errors.Is(err, pomodoro.ErrNoIntervals) || errors.Is(err, pomodoro.ErrConflict) {
+		return
+	}
+	slog.Error("Ошибка репозитория", append([]any{"op", op, "error", err}, args...)...)
+}
+
+func (r loggingRepo) Create(i pomodoro.Interval) (int64, error) {
+	id, err := r.repo.Create(i)
+	logError("create", err, "category", i.Category)
+	return id, err
+}
+
+// Вложенный репозиторий может и не уметь создавать интервалы разом -
+// тогда CreateMany создаёт их по одному
+func (r loggingRepo) CreateMany(intervals []pomodoro.Interval) ([]int64, error) {
+	ids, err := pomodoro.CreateMany(r.repo, intervals)
+	logError("create_many", err, "count", len(intervals))
+	return ids, err
+}
+
+func (r loggingRepo) Update(i pomodoro.Interval) error {
+	err := r.repo.Update(i)
+	logError("update", err, "id", i.ID)
+	return err
+}
+
+func (r loggingRepo) ByID(id int64) (pomodoro.Interval, error) {
+	i, err := r.repo.ByID(id)
+	logError("by_id", err, "id", id)
+	return i, err
+}
+
+func (r loggingRepo) Last() (pomodoro.Interval, error) {
+	i, err := r.repo.Last()
+	logError("last", err)
+	return i, err
+}
+
+func (r loggingRepo) Recent(n int) ([]pomodoro.Interval, error) {
+	intervals, err := r.repo.Recent(n)
+	logError("recent", err, "n", n)
+	return intervals, err
+}
+
+func (r loggingRepo) Range(from, to time.Time) ([]pomodoro.Interval, error) {
+	intervals, err := r.repo.Range(from, to)
+	logError("range", err, "from", from, "to", to)
+	return intervals, err
+}
+
+func (r loggingTaskRepo) CreateTask(t pomodoro.Task) (int64, error) {
+	id, err := r.tasks.CreateTask(t)
+	logError("create_task", err, "title", t.Title)
+	return id, err
+}
+
+func (r loggingTaskRepo) UpdateTask(t pomodoro.Task) error {
+	err := r.tasks.UpdateTask(t)
+	logError("update_task", err, "id", t.ID)
+	return err
+}
+
+func (r loggingTaskRepo) TaskByID(id int64) (pomodoro.Task, error) {
+	t, err := r.tasks.TaskByID(id)
+	logError("task_by_id", err, "id", id)
+	return t, err
+}
+
+func (r loggingTaskRepo) Tasks() ([]pomodoro.Task, error) {
+	tasks, err := r.tasks.Tasks()
+	logError("tasks", err)
+	return tasks, err
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"cmp"
+	"slices"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+// Возвращает n самых свежих интервалов, самый свежий - первый.
+//
+// Свежесть - не порядок создания, а время начала: сначала неначатые
+// (они всегда "текущие"), затем по убыванию StartTime, при равенстве -
+// по убыванию ID. Так импортированная история, которая создаётся позже
+// текущих интервалов, не становится "последним" интервалом и не сбивает
+// расписание. Завершённые и отменённые без времени начала (так раньше
+// пропускали неначатые) - самые старые, между собой по ID.
+func newest(intervals []pomodoro.Interval, n int) []pomodoro.Interval {
+	sorted := slices.Clone(intervals)
+	slices.SortFunc(sorted, func(a, b pomodoro.Interval) int {
+		aCurrent := a.State == pomodoro.StateNotStarted
+		bCurrent := b.State == pomodoro.StateNotStarted
+		switch {
+		case aCurrent != bCurrent:
+			if aCurrent {
+				return -1
+			}
+			return 1
+		case !a.StartTime.Equal(b.StartTime):
+			return b.StartTime.Compare(a.StartTime)
+		default:
+			return cmp.Compare(b.ID, a.ID)
+		}
+	})
+
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
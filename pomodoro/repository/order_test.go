@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestNewest(t *testing.T) {
+	now := time.Now()
+
+	notStarted := pomodoro.Interval{ID: 1, State: pomodoro.StateNotStarted}
+	running := pomodoro.Interval{ID: 2, State: pomodoro.StateRunning, StartTime: now}
+	done := pomodoro.Interval{ID: 3, State: pomodoro.StateDone, StartTime: now.Add(-time.Hour)}
+	// Импортирован позже, но начался раньше всех
+	imported := pomodoro.Interval{ID: 4, State: pomodoro.StateDone, StartTime: now.Add(-24 * time.Hour)}
+	// Пропущенный неначатым до того, как пропуск стал ставить время начала
+	skipped := pomodoro.Interval{ID: 5, State: pomodoro.StateCancelled}
+	skippedEarlier := pomodoro.Interval{ID: 6, State: pomodoro.StateCancelled}
+	sameStart := pomodoro.Interval{ID: 7, State: pomodoro.StateDone, StartTime: done.StartTime}
+
+	testCases := []struct {
+		name      string
+		intervals []pomodoro.Interval
+		n         int
+		expect    []int64
+	}{
+		{name: "NotStartedFirst", intervals: []pomodoro.Interval{running, notStarted, done}, n: 3,
+			expect: []int64{1, 2, 3}},
+		{name: "ImportedNotLast", intervals: []pomodoro.Interval{done, imported}, n: 1,
+			expect: []int64{3}},
+		{name: "CancelledZeroStart", intervals: []pomodoro.Interval{skipped, running}, n: 2,
+			expect: []int64{2, 5}},
+		{name: "CancelledZeroStartByID", intervals: []pomodoro.Interval{skipped, imported, skippedEarlier}, n: 3,
+			expect: []int64{4, 6, 5}},
+		{name: "SameStartByID", intervals: []pomodoro.Interval{done, sameStart}, n: 2,
+			expect: []int64{7, 3}},
+		{name: "FewerThanN", intervals: []pomodoro.Interval{done}, n: 5,
+			expect: []int64{3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ids []int64
+			for _, i := range newest(tc.intervals, tc.n) {
+				ids = append(ids, i.ID)
+			}
+			if !slices.Equal(ids, tc.expect) {
+				t.Errorf("Ожидали порядок: %v, а получили: %v", tc.expect, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package pomodoro
+
+import (
+	"fmt"
+	"time"
+)
+
+// Тип интервала - категория с продолжительностью.
+// Категории - это данные: классические Pomodoro/ShortBreak/LongBreak
+// всего лишь типы расписания по умолчанию.
+type IntervalType struct {
+	Category string
+	// Продолжительность; 0 - открытый интервал (секундомер)
+	Duration time.Duration
+	// Перерыв или работа
+	Break bool
+}
+
+// Расписание - набор типов интервалов и их последовательность,
+// которая повторяется по кругу
+type Schedule struct {
+	Types    []IntervalType
+	Sequence []string
+}
+
+// Классическое расписание:
+// p - sb - p - sb - p - sb - p - lb
+func ClassicSchedule(pomodoro, shortBreak, longBreak time.Duration) Schedule {
+	return Schedule{
+		Types: []IntervalType{
+			{Category: CategoryPomodoro, Duration: pomodoro},
+			{Category: CategoryShortBreak, Duration: shortBreak, Break: true},
+			{Category: CategoryLongBreak, Duration: longBreak, Break: true},
+		},
+		Sequence: []string{
+			CategoryPomodoro, CategoryShortBreak,
+			CategoryPomodoro, CategoryShortBreak,
+			CategoryPomodoro, CategoryShortBreak,
+			CategoryPomodoro, CategoryLongBreak,
+		},
+	}
+}
+
+// Возвращает тип интервала по категории
+func (s Schedule) Type(category string) (IntervalType, bool) {
+	for _, t := range s.Types {
+		if t.Category == category {
+			return t, true
+		}
+	}
+	return IntervalType{}, false
+}
+
+// Проверяет расписание: последовательность не пуста, все категории в ней
+// описаны, у перерывов есть продолжительность
+func (s Schedule) Validate() error {
+	if len(s.Sequence) == 0 {
+		return fmt.Errorf("%w: пустая последовательность", ErrInvalidSchedule)
+	}
+
+	seen := map[string]bool{}
+	for _, t := range s.Types {
+		if t.Category == "" {
+			return fmt.Errorf("%w: тип интервала без имени", ErrInvalidSchedule)
+		}
+		if seen[t.Category] {
+			return fmt.Errorf("%w: тип %q описан дважды", ErrInvalidSchedule, t.Category)
+		}
+		seen[t.Category] = true
+
+		if t.Duration < 0 {
+			return fmt.Errorf("%w: отрицательная продолжительность %q", ErrInvalidSchedule, t.Category)
+		}
+		// Открытым может быть только рабочий интервал - перерыв должен заканчиваться сам
+		if t.Break && t.Duration == 0 {
+			return fmt.Errorf("%w: у перерыва %q нет продолжительности", ErrInvalidSchedule, t.Category)
+		}
+	}
+
+	for _, c := range s.Sequence {
+		if !seen[c] {
+			return fmt.Errorf("%w: тип %q не описан", ErrInvalidSchedule, c)
+		}
+	}
+	return nil
+}
+
+// Определяет следующий тип интервала по истории recent
+// (последние интервалы, самый свежий - первый).
+//
+// Ищем позицию в последовательности, на которой закончилась история:
+// для каждой позиции считаем, сколько последних интервалов совпадают
+// с последовательностью, если идти от этой позиции назад по кругу.
+// Побеждает позиция с самым длинным совпадением, при равенстве - более ранняя.
+// Если ничего не совпало (история пуста или из другого расписания) -
+// начинаем последовательность сначала.
+func (s Schedule) next(recent []Interval) IntervalType {
+	n := len(s.Sequence)
+	best, bestLen := -1, 0
+
+	for p := 0; p < n; p++ {
+		m := 0
+		for m < len(recent) && m < n && recent[m].Category == s.Sequence[(p-m+n)%n] {
+			m++
+		}
+		if m > bestLen {
+			best, bestLen = p, m
+		}
+	}
+
+	t, _ := s.Type(s.Sequence[(best+1)%n])
+	return t
+}
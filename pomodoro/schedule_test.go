@@ -0,0 +1,118 @@
+package pomodoro_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestScheduleValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		schedule pomodoro.Schedule
+		expErr   error
+	}{
+		{
+			name:     "Classic",
+			schedule: pomodoro.ClassicSchedule(time.Minute, time.Minute, time.Minute),
+		},
+		{
+			name:     "EmptySequence",
+			schedule: pomodoro.Schedule{},
+			expErr:   pomodoro.ErrInvalidSchedule,
+		},
+		{
+			name: "UnknownType",
+			schedule: pomodoro.Schedule{
+				Types:    []pomodoro.IntervalType{{Category: "Deep", Duration: time.Minute}},
+				Sequence: []string{"Deep", "Rest"},
+			},
+			expErr: pomodoro.ErrInvalidSchedule,
+		},
+		{
+			name: "Duplicate",
+			schedule: pomodoro.Schedule{
+				Types: []pomodoro.IntervalType{
+					{Category: "Deep", Duration: time.Minute},
+					{Category: "Deep", Duration: time.Hour},
+				},
+				Sequence: []string{"Deep"},
+			},
+			expErr: pomodoro.ErrInvalidSchedule,
+		},
+		{
+			name: "OpenEndedBreak",
+			schedule: pomodoro.Schedule{
+				Types: []pomodoro.IntervalType{
+					{Category: "Deep", Duration: time.Minute},
+					{Category: "Rest", Break: true},
+				},
+				Sequence: []string{"Deep", "Rest"},
+			},
+			expErr: pomodoro.ErrInvalidSchedule,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.schedule.Validate()
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("Ожидали ошибку: %v, а получили: %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestCustomSchedule(t *testing.T) {
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+
+	const duration = 1 * time.Millisecond
+	config := pomodoro.NewConfig(repo, duration, duration, duration)
+	// Ревью после каждых 4-х рабочих блоков
+	config.Schedule = pomodoro.Schedule{
+		Types: []pomodoro.IntervalType{
+			{Category: "Deep", Duration: 3 * duration},
+			{Category: "Rest", Duration: duration, Break: true},
+			{Category: "Review", Duration: 2 * duration, Break: true},
+		},
+		Sequence: []string{"Deep", "Rest", "Deep", "Rest", "Deep", "Rest", "Deep", "Review"},
+	}
+
+	for i := 1; i <= 16; i++ {
+		expCategory, expDuration, expBreak := "Rest", duration, true
+		switch {
+		case i%2 != 0:
+			expCategory, expDuration, expBreak = "Deep", 3*duration, false
+		case i%8 == 0:
+			expCategory, expDuration = "Review", 2*duration
+		}
+
+		t.Run(fmt.Sprintf("%s%d", expCategory, i), func(t *testing.T) {
+			ti, err := pomodoro.GetInterval(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			emptyF := func(pomodoro.Interval) {}
+			if err := ti.Start(context.Background(), config, emptyF, emptyF, emptyF); err != nil {
+				t.Fatal(err)
+			}
+
+			if ti.Category != expCategory {
+				t.Errorf("Ожидали категорию: %q, а получили: %q", expCategory, ti.Category)
+			}
+			if ti.PlannedDuration != expDuration {
+				t.Errorf("Ожидали продолжительность интервала: %q, а получили: %q",
+					expDuration, ti.PlannedDuration)
+			}
+			if ti.Break != expBreak {
+				t.Errorf("Ожидали перерыв: %t, а получили: %t", expBreak, ti.Break)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package pomodoro
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownTask = errors.New("неизвестная задача")
+	ErrInvalidTask = errors.New("неверная задача")
+	ErrTaskDone    = errors.New("задача уже выполнена")
+	ErrNoTasks     = errors.New("репозиторий не хранит задачи")
+)
+
+// Задача из списка задач. К задаче привязываются рабочие интервалы,
+// завершённые из них - фактические помидоры задачи.
+type Task struct {
+	ID      int64
+	Title   string
+	Project string
+	// Оценка в помидорах; 0 - без оценки
+	Estimate int
+	Done     bool
+	// Когда задачу добавили и когда отметили выполненной
+	Created   time.Time
+	Completed time.Time
+}
+
+// Репозиторий задач. Обычно его реализует тот же репозиторий,
+// что и интервалы, - задачи хранятся рядом с ними.
+type TaskRepository interface {
+	// Создаёт задачу, возвращает её ID
+	CreateTask(t Task) (int64, error)
+
+	// Обновляет задачу
+	UpdateTask(t Task) error
+
+	// Возвращает задачу по id
+	TaskByID(id int64) (Task, error)
+
+	// Возвращает все задачи в порядке добавления
+	Tasks() ([]Task, error)
+}
+
+// Репозиторий задач конфига - если репозиторий интервалов хранит и задачи
+func (c *IntevalConfig) TaskRepository() (TaskRepository, error) {
+	if tasks, ok := c.repo.(TaskRepository); ok {
+		return tasks, nil
+	}
+	return nil, ErrNoTasks
+}
+
+// Задача, которую получат новые интервалы
+func (c *IntevalConfig) ActiveTask() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.Task
+}
+
+// ID задачи из списка, которую получат новые интервалы; 0 - не из списка
+func (c *IntevalConfig) ActiveTaskID() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.TaskID
+}
+
+// Меняет задачу на ходу: её получат новые интервалы и текущий,
+// если он ещё не завершён. Возвращает текущий интервал.
+// Задача задаётся только текстом - привязка к списку задач снимается.
+func SetTask(config *IntevalConfig, task string) (Interval, error) {
+	return assignTask(config, task, 0)
+}
+
+// Привязывает к задаче из списка новые интервалы и текущий,
+// если он ещё не завершён. Возвращает текущий интервал.
+func AttachTask(config *IntevalConfig, id int64) (Interval, error) {
+	tasks, err := config.TaskRepository()
+	if err != nil {
+		return Interval{}, err
+	}
+	t, err := tasks.TaskByID(id)
+	if err != nil {
+		return Interval{}, err
+	}
+	if t.Done {
+		return Interval{}, fmt.Errorf("%w: #%d %s", ErrTaskDone, t.ID, t.Title)
+	}
+	return assignTask(config, t.Title, t.ID)
+}
+
+func assignTask(config *IntevalConfig, task string, id int64) (Interval, error) {
+	config.mu.Lock()
+	config.Task = task
+	config.TaskID = id
+	config.mu.Unlock()
+
+	i, err := config.repo.Last()
+	if err == ErrNoIntervals {
+		return i, nil
+	}
+	if err != nil {
+		return i, err
+	}
+
+	i, err = config.update(i.ID, func(i *Interval) error {
+		if i.State == StateDone || i.State == StateCancelled {
+			return errUnchanged
+		}
+		i.Task = task
+		i.TaskID = id
+		return nil
+	})
+	if errors.Is(err, errUnchanged) {
+		return i, nil
+	}
+	if err != nil {
+		return i, err
+	}
+	// TUI по событию пересчитывает, не вышла ли задача за оценку
+	config.emit(EventTask, i)
+	return i, nil
+}
+
+// Добавляет задачу в список
+func AddTask(config *IntevalConfig, t Task) (Task, error) {
+	t.Title = strings.TrimSpace(t.Title)
+	t.Project = strings.TrimSpace(t.Project)
+	if t.Title == "" {
+		return t, fmt.Errorf("%w: пустое название", ErrInvalidTask)
+	}
+	if t.Estimate < 0 {
+		return t, fmt.Errorf("%w: оценка %d меньше нуля", ErrInvalidTask, t.Estimate)
+	}
+
+	tasks, err := config.TaskRepository()
+	if err != nil {
+		return t, err
+	}
+	t.Done = false
+	t.Created = time.Now()
+	if t.ID, err = tasks.CreateTask(t); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+// Отмечает задачу выполненной. Если к ней привязаны новые интервалы -
+// привязка снимается, текст задачи остаётся.
+func CompleteTask(config *IntevalConfig, id int64) (Task, error) {
+	tasks, err := config.TaskRepository()
+	if err != nil {
+		return Task{}, err
+	}
+	t, err := tasks.TaskByID(id)
+	if err != nil {
+		return t, err
+	}
+	if t.Done {
+		return t, fmt.Errorf("%w: #%d %s", ErrTaskDone, t.ID, t.Title)
+	}
+
+	t.Done = true
+	t.Completed = time.Now()
+	if err := tasks.UpdateTask(t); err != nil {
+		return t, err
+	}
+
+	config.mu.Lock()
+	if config.TaskID == id {
+		config.TaskID = 0
+	}
+	config.mu.Unlock()
+	return t, nil
+}
+
+// Задача с фактом: сколько её рабочих интервалов завершено
+type TaskProgress struct {
+	Task
+	Actual int
+}
+
+// Факт против оценки: "3/4" или "3/-" для задачи без оценки
+func (p TaskProgress) Progress() string {
+	if p.Estimate == 0 {
+		return fmt.Sprintf("%d/-", p.Actual)
+	}
+	return fmt.Sprintf("%d/%d", p.Actual, p.Estimate)
+}
+
+// Задача вышла за оценку
+func (p TaskProgress) OverEstimate() bool {
+	return p.Estimate > 0 && p.Actual > p.Estimate
+}
+
+// Факт по задачам: завершённые рабочие интервалы каждой задачи
+func taskActuals(config *IntevalConfig) (map[int64]int, error) {
+	intervals, err := Query(config.repo, Filter{})
+	if err != nil {
+		return nil, err
+	}
+	actual := map[int64]int{}
+	for _, i := range intervals {
+		if i.TaskID != 0 && !i.Break && i.State == StateDone {
+			actual[i.TaskID]++
+		}
+	}
+	return actual, nil
+}
+
+// Задачи с фактом в порядке добавления; all - вместе с выполненными
+func TaskList(config *IntevalConfig, all bool) ([]TaskProgress, error) {
+	tasks, err := config.TaskRepository()
+	if err != nil {
+		return nil, err
+	}
+	list, err := tasks.Tasks()
+	if err != nil {
+		return nil, err
+	}
+	actual, err := taskActuals(config)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := []TaskProgress{}
+	for _, t := range list {
+		if t.Done && !all {
+			continue
+		}
+		progress = append(progress, TaskProgress{Task: t, Actual: actual[t.ID]})
+	}
+	return progress, nil
+}
+
+// Задача с фактом по ID
+func TaskStatus(config *IntevalConfig, id int64) (TaskProgress, error) {
+	tasks, err := config.TaskRepository()
+	if err != nil {
+		return TaskProgress{}, err
+	}
+	t, err := tasks.TaskByID(id)
+	if err != nil {
+		return TaskProgress{}, err
+	}
+	actual, err := taskActuals(config)
+	if err != nil {
+		return TaskProgress{}, err
+	}
+	return TaskProgress{Task: t, Actual: actual[t.ID]}, nil
+}
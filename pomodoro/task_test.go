@@ -0,0 +1,137 @@
+package pomodoro_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestAddTask(t *testing.T) {
+	testCases := []struct {
+		name   string
+		task   pomodoro.Task
+		expErr error
+	}{
+		{name: "Valid", task: pomodoro.Task{Title: " Отчёт ", Project: "work", Estimate: 3}},
+		{name: "NoEstimate", task: pomodoro.Task{Title: "Почта"}},
+		{name: "EmptyTitle", task: pomodoro.Task{Title: "  "}, expErr: pomodoro.ErrInvalidTask},
+		{name: "NegativeEstimate", task: pomodoro.Task{Title: "Ревью", Estimate: -1},
+			expErr: pomodoro.ErrInvalidTask},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, cleanup := getRepo(t)
+			defer cleanup()
+			config := pomodoro.NewConfig(repo, time.Minute, time.Minute, time.Minute)
+
+			task, err := pomodoro.AddTask(config, tc.task)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Fatalf("Ожидали ошибку: %q, а получили: %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tasks, err := config.TaskRepository()
+			if err != nil {
+				t.Fatal(err)
+			}
+			saved, err := tasks.TaskByID(task.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.Title != task.Title || saved.Done || saved.Created.IsZero() {
+				t.Errorf("Ожидали задачу: %+v, а получили: %+v", task, saved)
+			}
+		})
+	}
+}
+
+func TestTaskList(t *testing.T) {
+	repo, cleanup := getRepo(t)
+	defer cleanup()
+	config := pomodoro.NewConfig(repo, time.Minute, time.Minute, time.Minute)
+
+	report, err := pomodoro.AddTask(config, pomodoro.Task{Title: "Отчёт", Estimate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mail, err := pomodoro.AddTask(config, pomodoro.Task{Title: "Почта"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Привязываем к отчёту текущий интервал и завершаем его дважды:
+	// факт должен выйти за оценку
+	if _, err := pomodoro.AttachTask(config, report.ID); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		i, err := pomodoro.GetInterval(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i.TaskID != report.ID || i.Task != report.Title {
+			t.Fatalf("Ожидали интервал задачи #%d, а получили: %+v", report.ID, i)
+		}
+		i.State = pomodoro.StateDone
+		i.StartTime = time.Now()
+		if err := repo.Update(i); err != nil {
+			t.Fatal(err)
+		}
+		// Перерыв пропускаем - следующим снова будет помидор
+		if _, err := pomodoro.Skip(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list, err := pomodoro.TaskList(config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != report.ID || list[1].ID != mail.ID {
+		t.Fatalf("Ожидали задачи #%d и #%d, а получили: %+v", report.ID, mail.ID, list)
+	}
+	if list[0].Actual != 2 || !list[0].OverEstimate() {
+		t.Errorf("Ожидали факт 2 сверх оценки 1, а получили: %d", list[0].Actual)
+	}
+	if list[1].Actual != 0 || list[1].OverEstimate() {
+		t.Errorf("Ожидали задачу без факта и без оценки, а получили: %+v", list[1])
+	}
+
+	// Выполненная задача пропадает из списка открытых, привязка снимается
+	if _, err := pomodoro.CompleteTask(config, report.ID); err != nil {
+		t.Fatal(err)
+	}
+	if config.ActiveTaskID() != 0 {
+		t.Errorf("Ожидали снятую привязку, а получили задачу #%d", config.ActiveTaskID())
+	}
+	if _, err := pomodoro.AttachTask(config, report.ID); !errors.Is(err, pomodoro.ErrTaskDone) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrTaskDone, err)
+	}
+	if _, err := pomodoro.CompleteTask(config, report.ID); !errors.Is(err, pomodoro.ErrTaskDone) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrTaskDone, err)
+	}
+	if _, err := pomodoro.AttachTask(config, 42); !errors.Is(err, pomodoro.ErrUnknownTask) {
+		t.Errorf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrUnknownTask, err)
+	}
+
+	if list, err = pomodoro.TaskList(config, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ID != mail.ID {
+		t.Errorf("Ожидали только задачу #%d, а получили: %+v", mail.ID, list)
+	}
+	if list, err = pomodoro.TaskList(config, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || !list[0].Done {
+		t.Errorf("Ожидали обе задачи, первую - выполненной, а получили: %+v", list)
+	}
+}
@@ -0,0 +1,117 @@
+package pomodoro
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Интервал меняют с разных сторон сразу: tick раз в секунду, кнопки TUI,
+// HTTP API, pomo в соседнем терминале и фоновый pomo serve. Поэтому
+// изменение - это всегда "прочитать свежую копию, изменить, записать,
+// если никто не успел раньше": репозиторий сверяет Version и на устаревшую
+// копию отвечает ErrConflict - тогда изменение повторяется на новой копии.
+// Внутри процесса записи вдобавок идут по очереди.
+
+// Сколько раз повторять изменение при конфликте, прежде чем сдаться
+const updateAttempts = 10
+
+// Как часто tick записывает ход интервала в репозиторий. Переписывать
+// историю каждую секунду незачем: между записями ход живёт в конфиге
+// и попадает в репозиторий с любым другим изменением интервала.
+const progressEvery = 15 * time.Second
+
+var (
+	// Изменять нечего: интервал уже в нужном состоянии или его успели
+	// остановить. Функция изменения возвращает её, чтобы ничего не записывать.
+	errUnchanged = errors.New("интервал не изменён")
+	// Изменился только ход интервала - запись можно отложить
+	errDeferred = errors.New("запись отложена")
+)
+
+// Ход интервала, ещё не записанный в репозиторий
+type progress struct {
+	// Начало интервала - после Reset прежний ход уже не нужен
+	started time.Time
+	actual  time.Duration
+}
+
+// Очередь записей конфига и незаписанный ход интервалов.
+// Указатель - по той же причине, что и мьютекс профилей.
+type writes struct {
+	sync.Mutex
+	progress map[int64]progress
+}
+
+// Подставляет в интервал незаписанный ход. Возвращает true,
+// если интервал в репозитории от него отстал.
+func (w *writes) merge(i *Interval) bool {
+	p, ok := w.progress[i.ID]
+	if !ok || !p.started.Equal(i.StartTime) || p.actual <= i.ActualDuration {
+		return false
+	}
+	i.ActualDuration = p.actual
+	return true
+}
+
+// Изменяет интервал id функцией f и записывает. f получает свежую копию
+// из репозитория вместе с незаписанным ходом и при конфликте вызывается
+// снова - поэтому менять она должна только интервал. Ошибка f отменяет
+// запись и возвращается вместе с прочитанным интервалом; errUnchanged
+// при этом всё же записывает отставший ход. Возвращает записанный интервал.
+func (c *IntevalConfig) update(id int64, f func(i *Interval) error) (Interval, error) {
+	c.writes.Lock()
+	defer c.writes.Unlock()
+
+	var (
+		i   Interval
+		err error
+	)
+	for range updateAttempts {
+		if i, err = c.repo.ByID(id); err != nil {
+			return i, err
+		}
+		behind := c.writes.merge(&i)
+
+		result := f(&i)
+		switch {
+		case errors.Is(result, errDeferred):
+			c.writes.progress[id] = progress{started: i.StartTime, actual: i.ActualDuration}
+			return i, nil
+		case errors.Is(result, errUnchanged) && behind:
+			// Изменять нечего, но ход ещё не записан - записываем его
+		case result != nil:
+			return i, result
+		}
+
+		if err = c.repo.Update(i); err == nil {
+			delete(c.writes.progress, id)
+			i.Version++
+			return i, result
+		}
+		if !errors.Is(err, ErrConflict) {
+			return i, err
+		}
+	}
+	return i, err
+}
+
+// Записывает в репозиторий ход исполняющихся интервалов, который ещё
+// не записан. Нужен, когда процесс выходит, оставляя интервал исполняться, -
+// например, TUI, передавший интервал фоновому pomo serve.
+func (c *IntevalConfig) SaveProgress() error {
+	c.writes.Lock()
+	ids := make([]int64, 0, len(c.writes.progress))
+	for id := range c.writes.progress {
+		ids = append(ids, id)
+	}
+	c.writes.Unlock()
+
+	for _, id := range ids {
+		if _, err := c.update(id, func(*Interval) error { return errUnchanged }); err != nil &&
+			!errors.Is(err, errUnchanged) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package pomodoro
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Допустимые диапазоны продолжительностей. NewConfig молча подставляет
+// значения по умолчанию вместо нулевых, а Validate отвергает и нулевые,
+// и абсурдные - вроде Pomodoro на 2 секунды или перерыва на 10 часов.
+const (
+	MinWorkDuration  = time.Minute
+	MaxWorkDuration  = 4 * time.Hour
+	MinBreakDuration = time.Minute
+	MaxBreakDuration = 2 * time.Hour
+	// Перерыв во Flowtime не может быть длиннее работы
+	MaxFlowtimeRatio = 1.0
+)
+
+// Ошибка одной настройки профиля
+type FieldError struct {
+	Profile string
+	Field   string
+	Value   any
+	Reason  string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("профиль %q: %s = %v: %s", e.Profile, e.Field, e.Value, e.Reason)
+}
+
+// Все ошибки проверки конфигурации - сразу, а не первая попавшаяся
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, e := range v {
+		msgs = append(msgs, e.Error())
+	}
+	return fmt.Sprintf("%s:\n  %s", ErrInvalidConfig, strings.Join(msgs, "\n  "))
+}
+
+// Позволяет проверять ошибку через errors.Is(err, ErrInvalidConfig)
+func (v ValidationErrors) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// Проверяет набор профилей. Возвращает ValidationErrors или nil.
+func ValidateProfiles(profiles []Profile) error {
+	var errs ValidationErrors
+	for _, p := range profiles {
+		errs = append(errs, p.validate()...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (p Profile) validate() ValidationErrors {
+	var errs ValidationErrors
+	add := func(field string, value any, reason string) {
+		errs = append(errs, FieldError{Profile: p.Name, Field: field, Value: value, Reason: reason})
+	}
+	inRange := func(field string, d, min, max time.Duration) {
+		if d < min || d > max {
+			add(field, d, fmt.Sprintf("допустимо от %v до %v", min, max))
+		}
+	}
+
+	switch p.Mode {
+	case ModeClassic:
+		// Классические продолжительности используются, только если нет своего расписания
+		if len(p.Schedule.Sequence) == 0 {
+			inRange("pomo", p.PomodoroDuration, MinWorkDuration, MaxWorkDuration)
+			inRange("short", p.ShortBreakDuration, MinBreakDuration, MaxBreakDuration)
+			inRange("long", p.LongBreakDuration, MinBreakDuration, MaxBreakDuration)
+			if p.ShortBreakDuration > p.LongBreakDuration {
+				add("short", p.ShortBreakDuration, "короткий перерыв длиннее длинного")
+			}
+			if p.ShortBreakDuration >= p.PomodoroDuration {
+				add("short", p.ShortBreakDuration, "перерыв не короче Pomodoro")
+			}
+		}
+	case ModeFlowtime:
+		if err := ValidateFlowtimeRatios(p.FlowtimeRatios); err != nil {
+			add("flowtime", p.FlowtimeRatios, err.Error())
+		}
+		for _, r := range p.FlowtimeRatios {
+			if r.Ratio > MaxFlowtimeRatio {
+				add("flowtime", r.Ratio, "перерыв длиннее работы")
+			}
+		}
+		if len(p.Schedule.Sequence) > 0 {
+			add("sequence", p.Schedule.Sequence, "во Flowtime собственное расписание не используется")
+		}
+	default:
+		add("mode", p.Mode, fmt.Sprintf("допустимо %s или %s", ModeClassic, ModeFlowtime))
+	}
+
+	if len(p.Schedule.Sequence) == 0 {
+		return errs
+	}
+
+	if err := p.Schedule.Validate(); err != nil {
+		add("sequence", p.Schedule.Sequence, err.Error())
+		return errs
+	}
+
+	work := false
+	for _, c := range p.Schedule.Sequence {
+		t, _ := p.Schedule.Type(c)
+		work = work || !t.Break
+	}
+	if !work {
+		add("sequence", p.Schedule.Sequence, "в последовательности одни перерывы")
+	}
+
+	// Проверяем только типы из последовательности - остальные не используются
+	for _, t := range p.Schedule.Types {
+		if !slices.Contains(p.Schedule.Sequence, t.Category) {
+			continue
+		}
+		field := "types." + t.Category
+		switch {
+		case t.Break:
+			inRange(field, t.Duration, MinBreakDuration, MaxBreakDuration)
+		case t.Duration != 0:
+			// Нулевая продолжительность работы - открытый интервал, это допустимо
+			inRange(field, t.Duration, MinWorkDuration, MaxWorkDuration)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,110 @@
+package pomodoro_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+)
+
+func TestValidateProfiles(t *testing.T) {
+	classic := pomodoro.Profile{
+		Name:               pomodoro.DefaultProfile,
+		PomodoroDuration:   25 * time.Minute,
+		ShortBreakDuration: 5 * time.Minute,
+		LongBreakDuration:  15 * time.Minute,
+		Mode:               pomodoro.ModeClassic,
+		FlowtimeRatios:     pomodoro.DefaultFlowtimeRatios(),
+	}
+
+	testCases := []struct {
+		name      string
+		modify    func(p *pomodoro.Profile)
+		expFields []string
+	}{
+		{name: "Default", modify: func(p *pomodoro.Profile) {}},
+		{
+			name:      "TooShortPomodoro",
+			modify:    func(p *pomodoro.Profile) { p.PomodoroDuration = 2 * time.Second },
+			expFields: []string{"pomo", "short"},
+		},
+		{
+			name:      "TooLongBreak",
+			modify:    func(p *pomodoro.Profile) { p.LongBreakDuration = 10 * time.Hour },
+			expFields: []string{"long"},
+		},
+		{
+			name:      "ShortLongerThanLong",
+			modify:    func(p *pomodoro.Profile) { p.ShortBreakDuration = 20 * time.Minute },
+			expFields: []string{"short"},
+		},
+		{
+			name:      "UnknownMode",
+			modify:    func(p *pomodoro.Profile) { p.Mode = "weird" },
+			expFields: []string{"mode"},
+		},
+		{
+			name: "FlowtimeRatioTooBig",
+			modify: func(p *pomodoro.Profile) {
+				p.Mode = pomodoro.ModeFlowtime
+				p.FlowtimeRatios = []pomodoro.FlowtimeRatio{{Ratio: 2}}
+			},
+			expFields: []string{"flowtime"},
+		},
+		{
+			name: "OnlyBreaks",
+			modify: func(p *pomodoro.Profile) {
+				p.Schedule = pomodoro.Schedule{
+					Types:    []pomodoro.IntervalType{{Category: "Rest", Duration: time.Minute, Break: true}},
+					Sequence: []string{"Rest"},
+				}
+			},
+			expFields: []string{"sequence"},
+		},
+		{
+			name: "OpenEndedWork",
+			modify: func(p *pomodoro.Profile) {
+				p.Schedule = pomodoro.Schedule{
+					Types: []pomodoro.IntervalType{
+						{Category: "Deep"},
+						{Category: "Rest", Duration: time.Minute, Break: true},
+					},
+					Sequence: []string{"Deep", "Rest"},
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := classic
+			tc.modify(&p)
+
+			err := pomodoro.ValidateProfiles([]pomodoro.Profile{p})
+			if len(tc.expFields) == 0 {
+				if err != nil {
+					t.Fatalf("Не ожидали ошибку, а получили: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, pomodoro.ErrInvalidConfig) {
+				t.Fatalf("Ожидали ошибку: %q, а получили: %v", pomodoro.ErrInvalidConfig, err)
+			}
+
+			var verrs pomodoro.ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("Ожидали ValidationErrors, а получили: %T", err)
+			}
+			if len(verrs) != len(tc.expFields) {
+				t.Fatalf("Ожидали ошибок: %d, а получили: %v", len(tc.expFields), verrs)
+			}
+			for k, f := range tc.expFields {
+				if verrs[k].Field != f || verrs[k].Profile != p.Name {
+					t.Errorf("Ожидали ошибку в поле %q, а получили: %v", f, verrs[k])
+				}
+			}
+		})
+	}
+}
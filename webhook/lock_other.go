@@ -0,0 +1,14 @@
+//go:build !unix
+
+package webhook
+
+// Вне unix flock нет: процессы, которые пишут одну очередь одновременно,
+// могут потерять отправки друг друга
+type fileLock struct{}
+
+func newFileLock(path string) (*fileLock, error) {
+	return &fileLock{}, nil
+}
+
+func (l *fileLock) Lock() error   { return nil }
+func (l *fileLock) Unlock() error { return nil }
@@ -0,0 +1,43 @@
+//go:build unix
+
+package webhook
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// Блокировка файла очереди между процессами - flock на отдельном файле
+// рядом с ним: очередь при записи подменяется через rename,
+// и блокировка на ней осталась бы у старого файла
+type fileLock struct {
+	f *os.File
+}
+
+func newFileLock(path string) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileLock{f: f}, nil
+}
+
+// Ждёт, пока другие процессы отпустят файл, и захватывает его
+func (l *fileLock) Lock() error {
+	return flock(l.f, syscall.LOCK_EX)
+}
+
+func (l *fileLock) Unlock() error {
+	return flock(l.f, syscall.LOCK_UN)
+}
+
+func flock(f *os.File, how int) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), how)
+		// Ожидание блокировки прервал сигнал - ждём дальше
+		if !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+}
@@ -0,0 +1,458 @@
+// Исходящие вебхуки: POST JSON на заданные адреса при смене состояния интервала.
+//
+// События сначала попадают в очередь в файле, и только потом отправляются -
+// так неотправленные события переживают перезапуск pomo. Неудачные
+// отправки повторяются с экспоненциальной задержкой.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/pomodoro/export"
+)
+
+// Ошибки
+var (
+	ErrInvalidEndpoint = errors.New("неверный адрес вебхука")
+	ErrDelivery        = errors.New("вебхук не доставлен")
+)
+
+// События, которые можно отправлять. Тики не отправляются никогда.
+var Events = []string{
+	pomodoro.EventStart, pomodoro.EventResume, pomodoro.EventPause,
+	pomodoro.EventDone, pomodoro.EventCancel, pomodoro.EventInterrupt,
+	pomodoro.EventReset,
+}
+
+// Заголовки запроса
+const (
+	HeaderEvent     = "X-Pomo-Event"
+	HeaderDelivery  = "X-Pomo-Delivery"
+	HeaderSignature = "X-Pomo-Signature"
+)
+
+// Адрес, на который отправляются события
+type Endpoint struct {
+	URL string
+	// События для отправки; пусто - все из Events
+	Events []string
+	// Ключ подписи. Если задан, тело подписывается HMAC-SHA256:
+	// X-Pomo-Signature: sha256=<hex>
+	Secret string
+}
+
+// Проверяет адрес и список событий
+func (e Endpoint) Validate() error {
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %q - нужен http(s)://...", ErrInvalidEndpoint, e.URL)
+	}
+	for _, ev := range e.Events {
+		if !slices.Contains(Events, ev) {
+			return fmt.Errorf("%w: %q: неизвестное событие %q, допустимо: %v",
+				ErrInvalidEndpoint, e.URL, ev, Events)
+		}
+	}
+	return nil
+}
+
+func (e Endpoint) wants(event string) bool {
+	return len(e.Events) == 0 || slices.Contains(e.Events, event)
+}
+
+// Тело запроса
+type Payload struct {
+	ID       string        `json:"id"`
+	Event    string        `json:"event"`
+	Time     time.Time     `json:"time"`
+	Interval export.Record `json:"interval"`
+}
+
+// Отправка в очереди
+type delivery struct {
+	ID          string          `json:"id"`
+	URL         string          `json:"url"`
+	Event       string          `json:"event"`
+	Body        json.RawMessage `json:"body"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"next_attempt"`
+}
+
+// Настройки диспетчера
+type Options struct {
+	Endpoints []Endpoint
+	// Файл очереди; пусто - очередь только в памяти
+	QueueFile string
+	Client    *http.Client
+	// Сколько раз пытаться отправить, прежде чем сдаться
+	MaxAttempts int
+	// Задержка перед первым повтором; дальше удваивается до MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Куда сообщать о брошенных отправках и ошибках очереди. По умолчанию - в лог.
+	OnError func(error)
+}
+
+// Сколько отправка, взятая одним процессом, скрыта от других. Процесс
+// за это время отправит и уберёт её или отложит; если он упал - отправку
+// возьмёт другой.
+const claimFor = time.Minute
+
+// Диспетчер вебхуков.
+//
+// Файл очереди общий у TUI и pomo serve, поэтому каждое изменение очереди -
+// перечитать файл, изменить, записать - идёт под блокировкой файла:
+// иначе процесс затёр бы отправки, добавленные другим.
+type Dispatcher struct {
+	opts  Options
+	mu    sync.Mutex
+	queue []delivery
+	wake  chan struct{}
+	// Блокировка файла очереди; nil - очередь только в памяти
+	lock *fileLock
+}
+
+// Создаёт диспетчер и загружает очередь, оставшуюся с прошлого запуска
+func New(opts Options) (*Dispatcher, error) {
+	for _, e := range opts.Endpoints {
+		if err := e.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 10
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = 5 * time.Second
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = 30 * time.Minute
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(err error) { slog.Error("Вебхуки", "error", err) }
+	}
+
+	d := &Dispatcher{opts: opts, wake: make(chan struct{}, 1)}
+	if opts.QueueFile != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.QueueFile), 0o755); err != nil {
+			return nil, err
+		}
+		lock, err := newFileLock(opts.QueueFile + ".lock")
+		if err != nil {
+			return nil, err
+		}
+		d.lock = lock
+	}
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Подписчик на события интервалов: ставит событие в очередь для каждого
+// подходящего адреса. Отправляет Run - здесь только запись в очередь.
+func (d *Dispatcher) Publish(e pomodoro.Event) {
+	if !slices.Contains(Events, e.Type) {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var added []delivery
+	for _, ep := range d.opts.Endpoints {
+		if !ep.wants(e.Type) {
+			continue
+		}
+		id := newID()
+		body, err := json.Marshal(Payload{
+			ID:       id,
+			Event:    e.Type,
+			Time:     e.Time,
+			Interval: export.NewRecord(e.Interval),
+		})
+		if err != nil {
+			d.opts.OnError(err)
+			continue
+		}
+		added = append(added, delivery{ID: id, URL: ep.URL, Event: e.Type, Body: body})
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	err := d.modify(func() {
+		d.queue = append(d.queue, added...)
+	})
+	if err != nil {
+		d.opts.OnError(err)
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Сколько отправок ждёт в очереди
+func (d *Dispatcher) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.queue)
+}
+
+// Отправляет события из очереди, пока не завершится ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		next := d.deliverDue(ctx)
+
+		wait := time.Hour
+		if !next.IsZero() {
+			wait = max(time.Until(next), 0)
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// Отправляет все отправки, время которых пришло. Возвращает время
+// ближайшей следующей попытки; нулевое - очередь пуста.
+func (d *Dispatcher) deliverDue(ctx context.Context) time.Time {
+	now := time.Now()
+
+	d.mu.Lock()
+	var due []delivery
+	err := d.modify(func() {
+		for k, dl := range d.queue {
+			if dl.NextAttempt.After(now) {
+				continue
+			}
+			due = append(due, dl)
+			// Пока отправляем, другие процессы эту отправку не берут
+			d.queue[k].NextAttempt = now.Add(claimFor)
+		}
+	})
+	d.mu.Unlock()
+	if err != nil {
+		d.opts.OnError(err)
+	}
+
+	for _, dl := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		err := d.send(ctx, dl)
+		d.finish(dl, err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var next time.Time
+	for _, dl := range d.queue {
+		if next.IsZero() || dl.NextAttempt.Before(next) {
+			next = dl.NextAttempt
+		}
+	}
+	return next
+}
+
+// Обновляет очередь по результату отправки: удачная или брошенная
+// отправка удаляется, неудачная - откладывается
+func (d *Dispatcher) finish(dl delivery, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var dropped error
+	saveErr := d.modify(func() {
+		k := slices.IndexFunc(d.queue, func(q delivery) bool { return q.ID == dl.ID })
+		if k < 0 {
+			return
+		}
+
+		switch {
+		case err == nil:
+			d.queue = slices.Delete(d.queue, k, k+1)
+		case errors.Is(err, ErrInvalidEndpoint) || dl.Attempts+1 >= d.opts.MaxAttempts:
+			d.queue = slices.Delete(d.queue, k, k+1)
+			dropped = fmt.Errorf("%s %s: попыток %d, бросаем: %w", dl.Event, dl.URL, dl.Attempts+1, err)
+		default:
+			d.queue[k].Attempts++
+			d.queue[k].NextAttempt = time.Now().Add(d.backoff(d.queue[k].Attempts))
+		}
+	})
+	if dropped != nil {
+		d.opts.OnError(dropped)
+	}
+	if saveErr != nil {
+		d.opts.OnError(saveErr)
+	}
+}
+
+// Задержка после n неудачных попыток: BaseDelay * 2^(n-1), не больше MaxDelay
+func (d *Dispatcher) backoff(n int) time.Duration {
+	delay := d.opts.BaseDelay
+	for range n - 1 {
+		delay *= 2
+		if delay >= d.opts.MaxDelay {
+			return d.opts.MaxDelay
+		}
+	}
+	return delay
+}
+
+func (d *Dispatcher) send(ctx context.Context, dl delivery) error {
+	// Ключ берём из текущих настроек, а не из очереди - чтобы не хранить
+	// секреты в файле. Адрес убрали из настроек - отправлять некуда.
+	k := slices.IndexFunc(d.opts.Endpoints, func(e Endpoint) bool { return e.URL == dl.URL })
+	if k < 0 {
+		return fmt.Errorf("%w: %q больше нет в настройках", ErrInvalidEndpoint, dl.URL)
+	}
+	ep := d.opts.Endpoints[k]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dl.URL, bytes.NewReader(dl.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pomo-webhook")
+	req.Header.Set(HeaderEvent, dl.Event)
+	req.Header.Set(HeaderDelivery, dl.ID)
+	if ep.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(ep.Secret, dl.Body))
+	}
+
+	res, err := d.opts.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDelivery, err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%w: статус %d", ErrDelivery, res.StatusCode)
+	}
+	return nil
+}
+
+// Подпись тела: sha256=<hex HMAC-SHA256(secret, body)>.
+// Получатель сверяет её через hmac.Equal.
+func Sign(secret string, body []byte) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write(body)
+	return "sha256=" + hex.EncodeToString(m.Sum(nil))
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Изменяет очередь функцией f и записывает её. Файл очереди блокируется
+// на всё время и перечитывается перед f - чтобы f видела отправки
+// других процессов. Вызывать под мьютексом.
+func (d *Dispatcher) modify(f func()) error {
+	if d.lock == nil {
+		f()
+		return nil
+	}
+	if err := d.lock.Lock(); err != nil {
+		f()
+		return err
+	}
+	defer d.lock.Unlock()
+
+	// Файл не прочитался - меняем очередь в памяти, но файл не трогаем,
+	// чтобы не затереть его
+	if err := d.load(); err != nil {
+		f()
+		return err
+	}
+	f()
+	return d.save()
+}
+
+// Загружает очередь из файла; файла нет - очередь в памяти остаётся как есть
+func (d *Dispatcher) load() error {
+	if d.opts.QueueFile == "" {
+		return nil
+	}
+	b, err := os.ReadFile(d.opts.QueueFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(b, &d.queue); err != nil {
+		return fmt.Errorf("очередь вебхуков %s: %w", d.opts.QueueFile, err)
+	}
+	return nil
+}
+
+// Записывает очередь в файл атомарно - через временный файл и переименование.
+// Вызывается под мьютексом.
+func (d *Dispatcher) save() error {
+	if d.opts.QueueFile == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(d.opts.QueueFile), 0o755); err != nil {
+		return err
+	}
+
+	queue := d.queue
+	if queue == nil {
+		queue = []delivery{}
+	}
+	b, err := json.Marshal(queue)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(d.opts.QueueFile), filepath.Base(d.opts.QueueFile)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), d.opts.QueueFile)
+}
@@ -0,0 +1,309 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"vegorov.ru/go-cli/pomo/pomodoro"
+	"vegorov.ru/go-cli/pomo/webhook"
+)
+
+// Тестовый получатель: отвечает статусами из statuses по очереди
+// (дальше - 200) и запоминает принятые запросы
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+	got      chan struct{}
+}
+
+func newReceiver(statuses ...int) *receiver {
+	return &receiver{statuses: statuses, got: make(chan struct{}, 100)}
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	if status == http.StatusOK {
+		rc.requests = append(rc.requests, r)
+		rc.bodies = append(rc.bodies, body)
+	}
+	rc.mu.Unlock()
+
+	w.WriteHeader(status)
+	rc.got <- struct{}{}
+}
+
+// Ждёт n запросов к получателю
+func (rc *receiver) wait(t *testing.T, n int) {
+	t.Helper()
+	for range n {
+		select {
+		case <-rc.got:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Не дождались вебхука")
+		}
+	}
+}
+
+func event(typ string) pomodoro.Event {
+	return pomodoro.Event{
+		Type: typ,
+		Time: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+		Interval: pomodoro.Interval{
+			ID:              1,
+			StartTime:       time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+			PlannedDuration: 25 * time.Minute,
+			Category:        pomodoro.CategoryPomodoro,
+			State:           pomodoro.StateRunning,
+		},
+	}
+}
+
+// Ждёт, пока очередь диспетчера опустеет
+func waitEmpty(t *testing.T, d *webhook.Dispatcher) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for d.Pending() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Очередь не опустела: %d", d.Pending())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	rc := newReceiver()
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+
+	d, err := webhook.New(webhook.Options{
+		Endpoints: []webhook.Endpoint{
+			{URL: ts.URL + "/all", Secret: "s3cret"},
+			{URL: ts.URL + "/done", Events: []string{pomodoro.EventDone}},
+		},
+		OnError: func(err error) { t.Errorf("Не ожидали ошибку: %v", err) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Run(ctx)
+
+	d.Publish(event(pomodoro.EventStart))
+	d.Publish(event(pomodoro.EventTick)) // тики не отправляются
+	d.Publish(event(pomodoro.EventDone))
+
+	// start - только на /all, done - на оба
+	rc.wait(t, 3)
+	waitEmpty(t, d)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	paths := map[string][]string{}
+	for k, r := range rc.requests {
+		paths[r.URL.Path] = append(paths[r.URL.Path], r.Header.Get(webhook.HeaderEvent))
+
+		if r.URL.Path == "/all" {
+			if sig := r.Header.Get(webhook.HeaderSignature); sig != webhook.Sign("s3cret", rc.bodies[k]) {
+				t.Errorf("Неверная подпись: %q", sig)
+			}
+		} else if r.Header.Get(webhook.HeaderSignature) != "" {
+			t.Error("Без ключа подписи быть не должно")
+		}
+
+		var p webhook.Payload
+		if err := json.Unmarshal(rc.bodies[k], &p); err != nil {
+			t.Fatal(err)
+		}
+		if p.ID != r.Header.Get(webhook.HeaderDelivery) || p.Interval.Category != pomodoro.CategoryPomodoro {
+			t.Errorf("Неверное тело: %+v", p)
+		}
+	}
+	if len(paths["/all"]) != 2 || len(paths["/done"]) != 1 || paths["/done"][0] != pomodoro.EventDone {
+		t.Errorf("Неверная раздача событий по адресам: %v", paths)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	rc := newReceiver(http.StatusInternalServerError, http.StatusBadGateway)
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+
+	d, err := webhook.New(webhook.Options{
+		Endpoints: []webhook.Endpoint{{URL: ts.URL}},
+		BaseDelay: 10 * time.Millisecond,
+		OnError:   func(err error) { t.Errorf("Не ожидали ошибку: %v", err) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Run(ctx)
+
+	d.Publish(event(pomodoro.EventPause))
+	// Две неудачи и успех
+	rc.wait(t, 3)
+	waitEmpty(t, d)
+}
+
+func TestGiveUp(t *testing.T) {
+	rc := newReceiver(500, 500, 500)
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+
+	failed := make(chan error, 1)
+	d, err := webhook.New(webhook.Options{
+		Endpoints:   []webhook.Endpoint{{URL: ts.URL}},
+		MaxAttempts: 2,
+		BaseDelay:   10 * time.Millisecond,
+		OnError:     func(err error) { failed <- err },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Run(ctx)
+
+	d.Publish(event(pomodoro.EventCancel))
+	select {
+	case err := <-failed:
+		if !errors.Is(err, webhook.ErrDelivery) {
+			t.Errorf("Ожидали ошибку: %q, а получили: %v", webhook.ErrDelivery, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Не дождались отказа от отправки")
+	}
+	if d.Pending() != 0 {
+		t.Errorf("Ожидали пустую очередь, а в ней: %d", d.Pending())
+	}
+}
+
+func TestPersistentQueue(t *testing.T) {
+	rc := newReceiver()
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+
+	opts := webhook.Options{
+		Endpoints: []webhook.Endpoint{{URL: ts.URL}},
+		QueueFile: filepath.Join(t.TempDir(), "webhooks.json"),
+	}
+
+	// Первый запуск: событие в очереди, но Run не запущен - как если бы
+	// pomo завершился, не успев отправить
+	d, err := webhook.New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Publish(event(pomodoro.EventDone))
+
+	// Второй запуск подхватывает очередь и отправляет
+	d, err = webhook.New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Pending() != 1 {
+		t.Fatalf("Ожидали в очереди: 1, а получили: %d", d.Pending())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Run(ctx)
+
+	rc.wait(t, 1)
+	waitEmpty(t, d)
+
+	d, err = webhook.New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Pending() != 0 {
+		t.Errorf("Ожидали пустую очередь после отправки, а в ней: %d", d.Pending())
+	}
+}
+
+// Одна очередь у TUI и pomo serve: отправки одного процесса
+// не затираются другим и уходят по одному разу
+func TestSharedQueue(t *testing.T) {
+	rc := newReceiver()
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+
+	opts := webhook.Options{
+		Endpoints: []webhook.Endpoint{{URL: ts.URL}},
+		QueueFile: filepath.Join(t.TempDir(), "webhooks.json"),
+	}
+	open := func() *webhook.Dispatcher {
+		d, err := webhook.New(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	d1, d2 := open(), open()
+	d1.Publish(event(pomodoro.EventStart))
+	d2.Publish(event(pomodoro.EventDone))
+	if n := open().Pending(); n != 2 {
+		t.Fatalf("Ожидали в очереди: 2, а получили: %d", n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d1.Run(ctx)
+	go d2.Run(ctx)
+
+	rc.wait(t, 2)
+	select {
+	case <-rc.got:
+		t.Error("Отправку доставили дважды")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		endpoint webhook.Endpoint
+		valid    bool
+	}{
+		{"HTTPS", webhook.Endpoint{URL: "https://chat.example/hook"}, true},
+		{"Events", webhook.Endpoint{URL: "http://localhost:8080", Events: []string{"start", "done"}}, true},
+		{"NoScheme", webhook.Endpoint{URL: "chat.example/hook"}, false},
+		{"FTP", webhook.Endpoint{URL: "ftp://chat.example"}, false},
+		{"Tick", webhook.Endpoint{URL: "https://chat.example", Events: []string{"tick"}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.endpoint.Validate()
+			if tc.valid && err != nil {
+				t.Errorf("Не ожидали ошибку, а получили: %v", err)
+			}
+			if !tc.valid && !errors.Is(err, webhook.ErrInvalidEndpoint) {
+				t.Errorf("Ожидали ошибку: %q, а получили: %v", webhook.ErrInvalidEndpoint, err)
+			}
+		})
+	}
+}